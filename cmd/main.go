@@ -3,12 +3,18 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
 
 	// _ "net/http/pprof"
 
 	"github.com/apfelfrisch/gosnake/engine"
 	"github.com/apfelfrisch/gosnake/engine/scenes"
 	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/apfelfrisch/gosnake/game/replay"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/joelschutz/stagehand"
 )
@@ -22,6 +28,20 @@ func main() {
 	// serverAddr := flag.String("server-addr", ":1200", "Set Sever Address")
 	// onlyServer := flag.Bool("only-server", false, "Run only the server")
 	// onlyClient := flag.Bool("only-client", false, "Run only the server")
+	replayDir := flag.String("record", "", "Record hosted matches into this directory")
+	replayFile := flag.String("replay", "", "Watch a recorded match")
+	mapFile := flag.String("map", "", "Custom map file for hosted games and the map editor")
+	rulesFile := flag.String("rules", game.DefaultRulesPreset, "Rules preset ("+strings.Join(game.RulesPresets(), ", ")+") or rules file for hosted games")
+	name := flag.String("name", "", "Player name shown to the other players")
+	snakeColor := flag.String("color", "", "Preferred snake color as hex RRGGBB")
+	listenAddr := flag.String("listen", ":1200", "Address hosted games listen on, port 0 picks a free port")
+	defaultNetwork := "udp"
+	if runtime.GOOS == "js" {
+		// Browsers can only open WebSockets
+		defaultNetwork = "ws"
+	}
+	network := flag.String("network", defaultNetwork, "Network for hosting and joining games: udp, tcp or ws, tcp works where udp is blocked and browser clients need ws")
+	allowOrigins := flag.String("allow-origins", "", "Comma separated hosts of the pages browser clients may join hosted ws games from, * for any")
 
 	flag.Parse()
 
@@ -38,7 +58,42 @@ func main() {
 	// 	log.Fatal(err)
 	// }
 
-	s := scenes.New()
+	rules, err := game.LoadRules(*rulesFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	profile := payload.Profile{Name: *name}
+	if *snakeColor != "" {
+		color, err := strconv.ParseUint(strings.TrimPrefix(*snakeColor, "#"), 16, 24)
+		if err != nil {
+			log.Fatalf("Invalid color %q: %v", *snakeColor, err)
+		}
+		profile.Color = uint32(color)
+	}
+
+	config := scenes.Config{
+		ReplayDir:  *replayDir,
+		MapFile:    *mapFile,
+		Rules:      &rules,
+		Profile:    profile,
+		ListenAddr: *listenAddr,
+		Network:    *network,
+	}
+	if *allowOrigins != "" {
+		config.AllowedOrigins = strings.Split(*allowOrigins, ",")
+	}
+
+	var s stagehand.Scene[game.GameState] = scenes.New(config)
+
+	if *replayFile != "" {
+		viewer, err := loadReplay(*replayFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		s = viewer
+	}
+
 	sm := stagehand.NewSceneManager[game.GameState](s, game.Paused)
 
 	if err := ebiten.RunGame(sm); err != nil {
@@ -46,6 +101,21 @@ func main() {
 	}
 }
 
+func loadReplay(file string) (*scenes.ReplayViewer, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := replay.Read(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return scenes.NewReplayViewer(r)
+}
+
 // func buildServer(playerCount int, addr string) *netServer.GameServer {
 // 	return netServer.New(
 // 		playerCount,
@@ -0,0 +1,254 @@
+// Command snake-server runs a dedicated game server without graphics or
+// audio. The match starts as soon as every client is ready.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/network/server"
+)
+
+// levelMaps is the map name of the embedded levels in the map rotation.
+const levelMaps = "levels"
+
+// levelSize is the width and height of the embedded levels in fields.
+const levelSize = 50
+
+// Config can be loaded from a JSON file, flags override its values.
+type Config struct {
+	Listen string `json:"listen"`
+	// Network is "udp", "tcp" or "ws", tcp works where udp is blocked and
+	// browser clients need ws.
+	Network string `json:"network"`
+	Players int    `json:"players"`
+	Bots    int    `json:"bots"`
+	Rules   string `json:"rules"`
+	// Maps are played in rotation, "levels" stands for the embedded levels.
+	Maps      []string `json:"maps"`
+	Name      string   `json:"name"`
+	ReplayDir string   `json:"record"`
+	LogFormat string   `json:"log_format"`
+	// AllowedOrigins are the hosts of the pages browser clients may join
+	// from over ws, "*" allows any page.
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+func main() {
+	config := Config{
+		Listen:    fmt.Sprintf(":%d", server.DefaultPort),
+		Network:   "udp",
+		Players:   2,
+		Rules:     game.DefaultRulesPreset,
+		Maps:      []string{levelMaps},
+		LogFormat: "text",
+	}
+
+	configFile := flag.String("config", "", "JSON config file, flags override its values")
+	listen := flag.String("listen", config.Listen, "Address to listen on, port 0 picks a free port")
+	network := flag.String("network", config.Network, "Network the clients connect with: udp, tcp or ws for browser clients")
+	allowOrigins := flag.String("allow-origins", "", "Comma separated hosts of the pages browser clients may join from over ws, * for any")
+	players := flag.Int("players", config.Players, "Player count, bots included")
+	bots := flag.Int("bots", config.Bots, "Bots taking the last player slots")
+	rules := flag.String("rules", config.Rules, "Rules preset ("+strings.Join(game.RulesPresets(), ", ")+") or rules file")
+	maps := flag.String("maps", strings.Join(config.Maps, ","), "Comma separated map files played in rotation, \""+levelMaps+"\" for the embedded levels")
+	name := flag.String("name", config.Name, "Announce the server on the LAN under this name")
+	replayDir := flag.String("record", config.ReplayDir, "Record matches into this directory")
+	logFormat := flag.String("log-format", config.LogFormat, "Log format, text or json")
+
+	flag.Parse()
+
+	if *configFile != "" {
+		if err := loadConfig(*configFile, &config); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "listen":
+			config.Listen = *listen
+		case "network":
+			config.Network = *network
+		case "allow-origins":
+			config.AllowedOrigins = strings.Split(*allowOrigins, ",")
+		case "players":
+			config.Players = *players
+		case "bots":
+			config.Bots = *bots
+		case "rules":
+			config.Rules = *rules
+		case "maps":
+			config.Maps = strings.Split(*maps, ",")
+		case "name":
+			config.Name = *name
+		case "record":
+			config.ReplayDir = *replayDir
+		case "log-format":
+			config.LogFormat = *logFormat
+		}
+	})
+
+	if err := setupLogging(config.LogFormat); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, config); err != nil {
+		slog.Error("Server failed", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Server stopped")
+}
+
+func run(ctx context.Context, config Config) error {
+	gameRules, err := game.LoadRules(config.Rules)
+	if err != nil {
+		return err
+	}
+
+	rotation, err := loadMaps(config.Maps)
+	if err != nil {
+		return err
+	}
+
+	g, err := game.NewGame(config.Players, levelSize, levelSize, gameRules, 0)
+	if err != nil {
+		return err
+	}
+
+	transport, err := server.NewTransport(config.Network, config.Listen, config.Players)
+	if err != nil {
+		return err
+	}
+	if ws, ok := transport.(*server.WebsocketServer); ok {
+		ws.AllowOrigins(config.AllowedOrigins...)
+	}
+
+	gameServer := server.New(config.Players, transport, g)
+
+	err = gameServer.Configure(server.Settings{
+		Players: config.Players,
+		Bots:    config.Bots,
+		Rules:   gameRules,
+		Map:     rotation[0].Map,
+		MapName: rotation[0].Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	gameServer.MapRotation(rotation)
+	gameServer.StartWhenReady()
+
+	if config.Name != "" {
+		gameServer.Announce(config.Name)
+	}
+
+	if config.ReplayDir != "" {
+		gameServer.Record(func() (io.Writer, error) {
+			return createReplayFile(config.ReplayDir)
+		})
+	}
+
+	if err := gameServer.Listen(); err != nil {
+		return err
+	}
+
+	slog.Info("Server listening",
+		"addr", gameServer.Addr().String(),
+		"network", config.Network,
+		"players", config.Players,
+		"bots", config.Bots,
+		"rules", gameRules.Name,
+		"maps", config.Maps,
+	)
+
+	gameServer.Run(ctx)
+
+	return nil
+}
+
+func loadConfig(file string, config *Config) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	return nil
+}
+
+func loadMaps(files []string) ([]server.NamedMap, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no maps given")
+	}
+
+	maps := make([]server.NamedMap, 0, len(files))
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == levelMaps {
+			maps = append(maps, server.NamedMap{Name: levelMaps})
+			continue
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		gameMap, err := game.ParseMap(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		maps = append(maps, server.NamedMap{Name: filepath.Base(file), Map: gameMap})
+	}
+
+	return maps, nil
+}
+
+// setupLogging routes the log package of the server through slog as well.
+func setupLogging(format string) error {
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, nil)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, nil)
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+
+	return nil
+}
+
+func createReplayFile(replayDir string) (*os.File, error) {
+	if err := os.MkdirAll(replayDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return os.Create(filepath.Join(replayDir, time.Now().Format("20060102-150405")+".replay"))
+}
@@ -2,13 +2,22 @@ package engine
 
 import (
 	"context"
+	"io"
 	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/apfelfrisch/gosnake/game"
 	netClient "github.com/apfelfrisch/gosnake/game/network/client"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
 	netServer "github.com/apfelfrisch/gosnake/game/network/server"
 )
 
+// correctionDecay is the part of a correction that is left after a frame.
+const correctionDecay = 0.8
+
 const (
 	DisplayWidth  = 1500
 	DisplayHeight = 1000
@@ -34,6 +43,10 @@ type ClientSnake struct {
 	ServerSnake game.Snake
 	InterPixel  int
 	IsGrowing   bool
+	// CorrectionX and CorrectionY shift the snake after a misprediction
+	// and shrink with every frame, so it slides to the corrected place.
+	CorrectionX float32
+	CorrectionY float32
 }
 
 func (cs *ClientSnake) OutOfsync(serverSnake game.Snake) bool {
@@ -41,6 +54,19 @@ func (cs *ClientSnake) OutOfsync(serverSnake game.Snake) bool {
 }
 
 func (cs *ClientSnake) Sync(serverSnake game.Snake) {
+	if len(cs.ServerSnake.Occupied) > 0 && len(serverSnake.Occupied) > 0 {
+		from, to := cs.ServerSnake.Head(), serverSnake.Head()
+		dx := float32(from.X) - float32(to.X)
+		dy := float32(from.Y) - float32(to.Y)
+
+		// A move is one field, a corrected prediction jumps a few fields
+		// sideways or back
+		if distance := math.Abs(float64(dx)) + math.Abs(float64(dy)); distance > 1 && distance <= 3 {
+			cs.CorrectionX += dx * float32(cs.GridSize)
+			cs.CorrectionY += dy * float32(cs.GridSize)
+		}
+	}
+
 	cs.InterPixel = 0
 	if len(serverSnake.Occupied) != len(cs.ServerSnake.Occupied) {
 		cs.IsGrowing = true
@@ -68,12 +94,19 @@ func (cs *ClientSnake) interPos(direction game.Direction) interPosition {
 func (cs *ClientSnake) Positions(dir game.Direction, pixel int) []Rect {
 	cs.InterPixel += pixel
 
+	correctionX, correctionY := cs.CorrectionX, cs.CorrectionY
+	cs.CorrectionX *= correctionDecay
+	cs.CorrectionY *= correctionDecay
+	if math.Abs(float64(cs.CorrectionX))+math.Abs(float64(cs.CorrectionY)) < 1 {
+		cs.CorrectionX, cs.CorrectionY = 0, 0
+	}
+
 	bodies := make([]Rect, 0, len(cs.ServerSnake.Occupied))
 
 	for i, pos := range cs.ServerSnake.Occupied {
 		body := Rect{
-			X:      float32(pos.X*cs.GridSize - cs.GridSize),
-			Y:      float32(pos.Y*cs.GridSize - cs.GridSize),
+			X:      float32(pos.X*cs.GridSize-cs.GridSize) + correctionX,
+			Y:      float32(pos.Y*cs.GridSize-cs.GridSize) + correctionY,
 			Width:  float32(cs.GridSize),
 			Height: float32(cs.GridSize),
 		}
@@ -128,13 +161,31 @@ func (cs *ClientSnake) Positions(dir game.Direction, pixel int) []Rect {
 	return bodies
 }
 
-func ConnectClient(ctx context.Context, serverAddr string) (*netClient.GameClient, error) {
-	client, err := netClient.Connect(ctx, serverAddr, GameWidth/GridSize, GameHeight/GridSize)
+func ConnectClient(ctx context.Context, network, serverAddr string, profile payload.Profile) (*netClient.GameClient, error) {
+	client, err := netClient.Connect(ctx, network, serverAddr, GameWidth/GridSize, GameHeight/GridSize, profile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	addSounds(client)
+
+	return client, nil
+}
+
+func ConnectSpectator(ctx context.Context, network, serverAddr string, profile payload.Profile) (*netClient.GameClient, error) {
+	client, err := netClient.Spectate(ctx, network, serverAddr, GameWidth/GridSize, GameHeight/GridSize, profile)
 
 	if err != nil {
 		return nil, err
 	}
 
+	addSounds(client)
+
+	return client, nil
+}
+
+func addSounds(client *netClient.GameClient) {
 	player := NewPlayer()
 
 	client.EventBus.Add(netClient.PlayerHasEaten{}, func(event netClient.Event) {
@@ -155,14 +206,86 @@ func ConnectClient(ctx context.Context, serverAddr string) (*netClient.GameClien
 	client.EventBus.Add(netClient.GameHasEnded{}, func(event netClient.Event) {
 		player.PauseMusic()
 	})
+}
 
-	return client, nil
+type ServerOptions struct {
+	// ReplayDir enables match recording into a new replay file in this directory.
+	ReplayDir string
+	// Map is played in every level instead of the embedded levels.
+	Map     *game.Map
+	MapName string
+	// Bots take the last player slots, alternating between the bot kinds.
+	Bots int
+	// Rules default to the classic rules.
+	Rules *game.Rules
+	// AutoStart skips the ready check of the lobby.
+	AutoStart bool
+	// Name announces the server on the LAN, if set.
+	Name string
+	// Network the clients connect with, "udp" if empty.
+	Network string
+	// AllowedOrigins are the hosts of the pages browser clients may join
+	// from over "ws", see WebsocketServer.AllowOrigins.
+	AllowedOrigins []string
 }
 
-func BuildServer(playerCount int, addr string) *netServer.GameServer {
-	return netServer.New(
-		playerCount,
-		addr,
-		game.NewGame(playerCount, GameWidth/GridSize, GameHeight/GridSize),
-	)
+func BuildServer(playerCount int, addr string, opts ServerOptions) (*netServer.GameServer, error) {
+	rules := game.DefaultRules()
+	if opts.Rules != nil {
+		rules = *opts.Rules
+	}
+
+	if opts.Network == "" {
+		opts.Network = "udp"
+	}
+	g, err := game.NewGame(playerCount, GameWidth/GridSize, GameHeight/GridSize, rules, rand.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := netServer.NewTransport(opts.Network, addr, playerCount)
+	if err != nil {
+		return nil, err
+	}
+	if ws, ok := transport.(*netServer.WebsocketServer); ok {
+		ws.AllowOrigins(opts.AllowedOrigins...)
+	}
+
+	server := netServer.New(playerCount, transport, g)
+
+	err = server.Configure(netServer.Settings{
+		Players: playerCount,
+		Bots:    opts.Bots,
+		Rules:   rules,
+		Map:     opts.Map,
+		MapName: opts.MapName,
+	})
+	if err != nil {
+		transport.Disconnect()
+		return nil, err
+	}
+
+	if opts.AutoStart {
+		server.AutoStart()
+	}
+
+	if opts.Name != "" {
+		server.Announce(opts.Name)
+	}
+
+	if opts.ReplayDir != "" {
+		server.Record(func() (io.Writer, error) {
+			return createReplayFile(opts.ReplayDir)
+		})
+	}
+
+	return server, nil
+}
+
+func createReplayFile(replayDir string) (*os.File, error) {
+	if err := os.MkdirAll(replayDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return os.Create(filepath.Join(replayDir, time.Now().Format("20060102-150405")+".replay"))
 }
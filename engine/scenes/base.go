@@ -7,6 +7,7 @@ import (
 	"image/color"
 	"log"
 	"sort"
+	"time"
 
 	"github.com/apfelfrisch/gosnake/engine"
 	"github.com/apfelfrisch/gosnake/game"
@@ -70,8 +71,8 @@ func drawPlayerInfo(screen *ebiten.Image, payload *payload.Payload) {
 	op.ColorScale.ScaleWithColor(color.White)
 
 	sortPerkNames := func(perks game.Perks) []game.PerkType {
-		pNames := make([]game.PerkType, 0, len(payload.Player.Perks))
-		for key := range payload.Player.Perks {
+		pNames := make([]game.PerkType, 0, len(perks))
+		for key := range perks {
 			pNames = append(pNames, key)
 		}
 		sort.Slice(pNames, func(i, j int) bool {
@@ -81,31 +82,148 @@ func drawPlayerInfo(screen *ebiten.Image, payload *payload.Payload) {
 		return pNames
 	}
 
-	op.GeoM.Translate(playerInfoXOffset, 50)
-	text.Draw(screen, "Lives:", face, op)
-	op.GeoM.Translate(70, 0)
-	text.Draw(screen, fmt.Sprintf("%d", payload.Player.Lives), face, op)
-	op.GeoM.Translate(-70, 30)
-	text.Draw(screen, "Perks:", face, op)
-	op.GeoM.Translate(70, 0)
-	for _, pName := range sortPerkNames(payload.Player.Perks) {
-		text.Draw(screen, fmt.Sprintf("%v (%v)", pName, payload.Player.Perks[pName].Usages), face, op)
-		op.GeoM.Translate(0, 30)
+	snakes := append([]game.Snake{payload.Player}, payload.Opponents...)
+	names := make([]string, len(snakes))
+	for i := range names {
+		if i < len(payload.Profiles) {
+			names[i] = payload.Profiles[i].Name
+		}
 	}
 
-	for _, oppenent := range payload.Opponents {
-		op.GeoM.Translate(-70, 0)
-		text.Draw(screen, "---", face, op)
-		op.GeoM.Translate(0, 30)
+	order := make([]int, len(snakes))
+	for i := range order {
+		order[i] = i
+	}
+	if payload.Rules.Teams > 0 {
+		sort.SliceStable(order, func(i, j int) bool {
+			return snakes[order[i]].Team < snakes[order[j]].Team
+		})
+	}
+
+	op.GeoM.Translate(playerInfoXOffset, 50)
+	for i, index := range order {
+		snake := snakes[index]
+		newTeam := payload.Rules.Teams > 0 && (i == 0 || snakes[order[i-1]].Team != snake.Team)
+		if newTeam {
+			teamOp := &text.DrawOptions{}
+			teamOp.GeoM = op.GeoM
+			teamOp.ColorScale.ScaleWithColor(teamColor(snake.Team, false))
+			text.Draw(screen, fmt.Sprintf("Team %d", snake.Team), face, teamOp)
+			op.GeoM.Translate(0, 30)
+		}
+
+		if names[index] != "" {
+			text.Draw(screen, names[index], face, op)
+			op.GeoM.Translate(0, 30)
+		} else if i > 0 && !newTeam {
+			text.Draw(screen, "---", face, op)
+			op.GeoM.Translate(0, 30)
+		}
+
 		text.Draw(screen, "Lives:", face, op)
 		op.GeoM.Translate(70, 0)
-		text.Draw(screen, fmt.Sprintf("%d", oppenent.Lives), face, op)
+		text.Draw(screen, livesText(snake), face, op)
 		op.GeoM.Translate(-70, 30)
 		text.Draw(screen, "Perks:", face, op)
 		op.GeoM.Translate(70, 0)
-		for _, pName := range sortPerkNames(oppenent.Perks) {
-			text.Draw(screen, fmt.Sprintf("%v (%v)", pName, oppenent.Perks[pName].Usages), face, op)
+		for _, pName := range sortPerkNames(snake.Perks) {
+			perk := snake.Perks[pName]
+			if pName.IsTimed() {
+				text.Draw(screen, fmt.Sprintf("%v (%v Ticks)", pName, perk.Ticks), face, op)
+			} else {
+				text.Draw(screen, fmt.Sprintf("%v (%v)", pName, perk.Usages), face, op)
+			}
 			op.GeoM.Translate(0, 30)
 		}
+		op.GeoM.Translate(-70, 0)
+	}
+}
+
+func livesText(snake game.Snake) string {
+	if !snake.Alive {
+		return fmt.Sprintf("%d (raus)", snake.Lives)
+	}
+
+	return fmt.Sprintf("%d", snake.Lives)
+}
+
+// drawRules lists the rules from the position of op downwards and leaves op
+// below the last line.
+func drawRules(screen *ebiten.Image, rules game.Rules, op *text.DrawOptions) {
+	menuFont, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatal(err)
+	}
+	face := &text.GoTextFace{
+		Source: menuFont,
+		Size:   20.0,
+	}
+
+	perks := "aus"
+	if rules.PerksEnabled() {
+		perks = fmt.Sprintf("1 zu %d pro Tick", rules.PerkSpawnOdds)
+	}
+
+	mode := "Modus: Klassisch"
+	if rules.IsBattleRoyale() {
+		mode = "Modus: Battle Royale"
+		if rules.ShrinkTicks > 0 {
+			mode += fmt.Sprintf(", Karte schrumpft alle %d Ticks", rules.ShrinkTicks)
+		}
+	}
+
+	teams := "Teams: keine"
+	if rules.Teams > 0 {
+		teams = fmt.Sprintf("Teams: %d, gemeinsame Leben und Punkte", rules.Teams)
+		if rules.FriendlyFire {
+			teams += ", mit Friendly Fire"
+		}
+	}
+
+	headOn := "beide sterben"
+	if rules.HeadOn == game.HeadOnLongerWins {
+		headOn = "die längere Schlange gewinnt"
+	}
+
+	for _, line := range []string{
+		fmt.Sprintf("Regeln: %s", rules.Name),
+		fmt.Sprintf("Leben: %d, Tempo: %v pro Feld", rules.Lives, rules.TickDuration()),
+		fmt.Sprintf("Perks: %d zum Start, neue %s", rules.StartPerks, perks),
+		fmt.Sprintf("Levelwechsel nach %d Candies, Dash: %d Felder", rules.MapSwitch, rules.DashLength),
+		fmt.Sprintf("Ghost und Magnet: %d Ticks, Reverse: Taste R", rules.PerkTicks),
+		fmt.Sprintf("Frontal-Crash: %s", headOn),
+		mode,
+		teams,
+	} {
+		text.Draw(screen, line, face, op)
+		op.GeoM.Translate(0, 30)
 	}
 }
+
+// drawLatency shows the measured round trip time and jitter below the
+// player info.
+func drawLatency(screen *ebiten.Image, latency netClient.Latency) {
+	menuFont, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatal(err)
+	}
+	face := &text.GoTextFace{
+		Source: menuFont,
+		Size:   20.0,
+	}
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(color.Gray{180})
+
+	status := "Ping: wird gemessen"
+	if latency.RTT > 0 {
+		status = fmt.Sprintf("Ping: %.1f ms, Jitter: %.1f ms", milliseconds(latency.RTT), milliseconds(latency.Jitter))
+	}
+
+	op.GeoM.Translate(playerInfoXOffset, engine.DisplayHeight-40)
+	text.Draw(screen, status, face, op)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,119 @@
+package scenes
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/apfelfrisch/gosnake/game/network/discovery"
+	netServer "github.com/apfelfrisch/gosnake/game/network/server"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// updateServerList refreshes the servers found on the LAN, Tab picks the
+// next one.
+func (s *MenuStart) updateServerList() {
+	if s.browser == nil && !s.browseFailed {
+		browser, err := discovery.Browse()
+		if err != nil {
+			log.Println("Could not search for LAN servers:", err)
+			s.browseFailed = true
+			return
+		}
+		s.browser = browser
+	}
+
+	if s.browser == nil {
+		return
+	}
+
+	selected := ""
+	if s.selected >= 0 && s.selected < len(s.servers) {
+		selected = s.servers[s.selected].Addr
+	}
+
+	s.servers = s.browser.Servers()
+	s.selected = -1
+	for i, server := range s.servers {
+		if server.Addr == selected {
+			s.selected = i
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) && len(s.servers) > 0 {
+		s.selected = (s.selected + 1) % len(s.servers)
+		s.serverAddr = s.servers[s.selected].Addr
+		if network := s.servers[s.selected].Network; network != "" {
+			s.network = network
+		}
+	}
+}
+
+func (s *MenuStart) stopBrowsing() {
+	if s.browser != nil {
+		s.browser.Close()
+		s.browser = nil
+	}
+	s.servers = nil
+	s.selected = -1
+}
+
+// joinAddr is the typed or picked address, with the default port if it has
+// none.
+func (s *MenuStart) joinAddr() string {
+	if _, _, err := net.SplitHostPort(s.serverAddr); err == nil {
+		return s.serverAddr
+	}
+
+	host := strings.TrimSuffix(strings.TrimPrefix(s.serverAddr, "["), "]")
+
+	return net.JoinHostPort(host, strconv.Itoa(netServer.DefaultPort))
+}
+
+// isAddrChar reports if char can be part of a host:port address.
+func isAddrChar(char rune) bool {
+	return char >= '0' && char <= '9' ||
+		char >= 'a' && char <= 'z' ||
+		char >= 'A' && char <= 'Z' ||
+		strings.ContainsRune(".:-[]", char)
+}
+
+// serverName is announced on the LAN for hosted games.
+func (s *MenuStart) serverName() string {
+	if s.config.Profile.Name == "" {
+		return "Snake Server"
+	}
+
+	return "Spiel von " + s.config.Profile.Name
+}
+
+func (s *MenuStart) drawServerList(screen *ebiten.Image, face *text.GoTextFace, op *text.DrawOptions) {
+	op.GeoM.Reset()
+	op.GeoM.Translate(360, 250)
+
+	if s.browseFailed {
+		text.Draw(screen, "Suche im LAN nicht moeglich", face, op)
+		return
+	}
+
+	if len(s.servers) == 0 {
+		text.Draw(screen, "Suche Server im LAN"+s.blink.Show("..."), face, op)
+		return
+	}
+
+	text.Draw(screen, "Server im LAN (Tab: Auswahl)", face, op)
+
+	for i, server := range s.servers {
+		op.GeoM.Translate(0, 40)
+
+		marker := "  "
+		if i == s.selected {
+			marker = "->"
+		}
+		text.Draw(screen, fmt.Sprintf("%s %s - %s - %s - %d/%d Spieler", marker, server.Name, server.MapName, server.Rules, server.Players, server.Capacity), face, op)
+	}
+}
@@ -0,0 +1,287 @@
+package scenes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/apfelfrisch/gosnake/engine"
+	"github.com/apfelfrisch/gosnake/game"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const defaultMapFile = "custom-map.txt"
+
+type editorTool struct {
+	name  string
+	field rune
+	key   ebiten.Key
+	color color.Color
+}
+
+var editorTools = []editorTool{
+	{"Wand", game.MapWall, ebiten.Key1, color.Gray{150}},
+	{"Startpunkt", game.MapSpawn, ebiten.Key2, color.RGBA{30, 144, 255, 255}},
+	{"Candy-Zone", game.MapCandyZone, ebiten.Key3, color.RGBA{90, 90, 40, 255}},
+}
+
+type MapEditor struct {
+	BaseScene
+	menu     *MenuStart
+	gameMap  *game.Map
+	file     string
+	tool     int
+	message  string
+	ctx      context.Context
+	cancle   context.CancelFunc
+	testing  bool
+	testDone chan error
+}
+
+func NewMapEditor(menu *MenuStart) *MapEditor {
+	file := menu.config.MapFile
+	if file == "" {
+		file = defaultMapFile
+	}
+
+	gameMap, err := loadMapFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		gameMap = game.NewEmptyMap(engine.GameWidth/engine.GridSize, engine.GameHeight/engine.GridSize)
+	} else if err != nil {
+		log.Println("Could not load map:", err)
+		gameMap = game.NewEmptyMap(engine.GameWidth/engine.GridSize, engine.GameHeight/engine.GridSize)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &MapEditor{
+		BaseScene: menu.BaseScene,
+		menu:      menu,
+		gameMap:   gameMap,
+		file:      file,
+		ctx:       ctx,
+		cancle:    cancel,
+	}
+}
+
+func (s *MapEditor) Unload() game.GameState {
+	return game.Paused
+}
+
+func (s *MapEditor) Update() error {
+	if s.testing {
+		select {
+		case err := <-s.testDone:
+			s.testing = false
+			if err != nil {
+				s.message = "Testspiel fehlgeschlagen: " + err.Error()
+				return nil
+			}
+			s.sm.SwitchTo(&MenuPaused{BaseScene: s.BaseScene})
+		default:
+		}
+		return nil
+	}
+
+	for i, tool := range editorTools {
+		if inpututil.IsKeyJustPressed(tool.key) {
+			s.tool = i
+		}
+	}
+
+	if pos, ok := cursorField(); ok {
+		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			s.gameMap.SetField(pos, editorTools[s.tool].field)
+		} else if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+			s.gameMap.SetField(pos, game.MapEmpty)
+		}
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		s.cancle()
+		s.sm.SwitchTo(s.menu)
+	case ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyS):
+		s.save()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		s.test()
+	}
+
+	return nil
+}
+
+func (s *MapEditor) save() {
+	if err := s.gameMap.Validate(); err != nil {
+		s.message = "Ungueltige Karte: " + err.Error()
+		return
+	}
+
+	data, _ := s.gameMap.MarshalText()
+	if err := os.WriteFile(s.file, data, 0o644); err != nil {
+		s.message = "Speichern fehlgeschlagen: " + err.Error()
+		return
+	}
+
+	s.message = "Gespeichert: " + s.file
+}
+
+// test starts a local singleplayer server with the edited map.
+func (s *MapEditor) test() {
+	if err := s.gameMap.Validate(); err != nil {
+		s.message = "Ungueltige Karte: " + err.Error()
+		return
+	}
+
+	server, err := engine.BuildServer(1, "127.0.0.1:0", engine.ServerOptions{
+		ReplayDir: s.menu.config.ReplayDir,
+		Map:       s.gameMap.Clone(),
+		MapName:   s.file,
+		Rules:     s.menu.config.Rules,
+		AutoStart: true,
+	})
+	if err == nil {
+		err = server.RunBackground(s.ctx)
+	}
+	if err != nil {
+		s.message = "Testspiel fehlgeschlagen: " + err.Error()
+		return
+	}
+
+	s.testing = true
+	s.testDone = make(chan error, 1)
+	s.message = "Starte Testspiel..."
+
+	go func() {
+		client, err := engine.ConnectClient(s.ctx, "udp", localAddr(server.Addr()), s.menu.config.Profile)
+		if err != nil {
+			s.testDone <- err
+			return
+		}
+		s.client = client
+		s.testDone <- nil
+	}()
+}
+
+func (s *MapEditor) Draw(screen *ebiten.Image) {
+	for _, fieldPos := range s.gameMap.World() {
+		field := s.gameMap.MapField(fieldPos.Position)
+
+		var c color.Color
+		for _, tool := range editorTools {
+			if tool.field == field {
+				c = tool.color
+			}
+		}
+		if c == nil && (fieldPos.X+fieldPos.Y)%2 == 0 {
+			c = color.RGBA{13, 13, 13, 255}
+		}
+		if c == nil {
+			continue
+		}
+
+		vector.DrawFilledRect(
+			screen,
+			float32(fieldPos.X*engine.GridSize-engine.GridSize),
+			float32(fieldPos.Y*engine.GridSize-engine.GridSize),
+			float32(engine.GridSize),
+			float32(engine.GridSize),
+			c,
+			false,
+		)
+	}
+
+	if pos, ok := cursorField(); ok {
+		vector.StrokeRect(
+			screen,
+			float32(pos.X*engine.GridSize-engine.GridSize),
+			float32(pos.Y*engine.GridSize-engine.GridSize),
+			float32(engine.GridSize),
+			float32(engine.GridSize),
+			2,
+			color.White,
+			false,
+		)
+	}
+
+	s.drawPanel(screen)
+}
+
+func (s *MapEditor) drawPanel(screen *ebiten.Image) {
+	menuFont, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatal(err)
+	}
+	face := &text.GoTextFace{
+		Source: menuFont,
+		Size:   20.0,
+	}
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(color.White)
+
+	op.GeoM.Translate(playerInfoXOffset, 50)
+	text.Draw(screen, "Karte: "+s.file, face, op)
+
+	for i, tool := range editorTools {
+		op.GeoM.Translate(0, 30)
+		marker := "  "
+		if i == s.tool {
+			marker = "->"
+		}
+		text.Draw(screen, fmt.Sprintf("%s %d: %s", marker, i+1, tool.name), face, op)
+	}
+
+	op.GeoM.Translate(0, 60)
+	for _, help := range []string{
+		"Linke Maustaste: Malen",
+		"Rechte Maustaste: Loeschen",
+		"Strg+S: Speichern",
+		"Enter: Testspiel",
+		"Esc: Zurueck",
+	} {
+		text.Draw(screen, help, face, op)
+		op.GeoM.Translate(0, 30)
+	}
+
+	if s.message != "" {
+		op.GeoM.Translate(0, 30)
+		text.Draw(screen, s.message, face, op)
+	}
+}
+
+// cursorField returns the map position below the mouse cursor.
+func cursorField() (game.Position, bool) {
+	x, y := ebiten.CursorPosition()
+	if x < 0 || y < 0 || x >= engine.GameWidth || y >= engine.GameHeight {
+		return game.Position{}, false
+	}
+
+	return game.Position{
+		Y: uint16(y/engine.GridSize + 1),
+		X: uint16(x/engine.GridSize + 1),
+	}, true
+}
+
+func loadMapFile(file string) (*game.Map, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gameMap, err := game.ParseMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return gameMap, nil
+}
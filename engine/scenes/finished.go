@@ -35,6 +35,7 @@ func (s *MenuFinished) Update() error {
 func (s *MenuFinished) Draw(screen *ebiten.Image) {
 	drawFinishScreen(screen, s.client.Payload.Player)
 	drawPlayerInfo(screen, s.client.Payload)
+	drawLatency(screen, s.client.Latency())
 }
 
 func drawFinishScreen(screen *ebiten.Image, player game.Snake) {
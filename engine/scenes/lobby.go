@@ -0,0 +1,211 @@
+package scenes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/apfelfrisch/gosnake/game"
+	netServer "github.com/apfelfrisch/gosnake/game/network/server"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const levelMapsName = "Level-Karten"
+
+type mapOption struct {
+	name    string
+	gameMap *game.Map
+}
+
+// updateLobby handles the keys while the server waits for the players. Only
+// the host can change the settings and start the match.
+func (s *MenuStart) updateLobby() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.leave()
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		s.client.ToggleReady()
+	}
+
+	if s.server == nil {
+		return
+	}
+
+	settings := s.server.Settings()
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		if err := s.server.Start(); err != nil {
+			s.message = "Start nicht moeglich: " + err.Error()
+		}
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		settings.Players = min(settings.Players+1, netServer.MaxPlayers)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		settings.Players = max(settings.Players-1, 2)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		settings.Bots++
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		settings.Bots = max(settings.Bots-1, 0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyR):
+		settings.Rules = nextRules(s.rulesOptions(), settings.Rules)
+	case inpututil.IsKeyJustPressed(ebiten.KeyM):
+		option := nextMap(s.mapOptions(), settings.MapName)
+		settings.Map, settings.MapName = option.gameMap, option.name
+	default:
+		return
+	}
+
+	if err := s.server.Configure(settings); err != nil {
+		s.message = "Einstellung nicht moeglich: " + err.Error()
+		return
+	}
+
+	s.message = ""
+	s.playerCount = settings.Players
+	s.botCount = settings.Bots
+}
+
+// leave frees the player slot, the host also closes the server.
+func (s *MenuStart) leave() {
+	s.client.Leave()
+	s.client = nil
+	s.server = nil
+	s.message = ""
+	s.connection = connClosed
+
+	s.cancle()
+	s.ctx, s.cancle = context.WithCancel(context.Background())
+}
+
+// rulesOptions are the rules the host can choose from, the configured rules
+// first.
+func (s *MenuStart) rulesOptions() []game.Rules {
+	var options []game.Rules
+	if s.config.Rules != nil {
+		options = append(options, *s.config.Rules)
+	}
+
+	for _, name := range game.RulesPresets() {
+		if s.config.Rules != nil && s.config.Rules.Name == name {
+			continue
+		}
+
+		if rules, err := game.RulesPreset(name); err == nil {
+			options = append(options, rules)
+		}
+	}
+
+	return options
+}
+
+func nextRules(options []game.Rules, current game.Rules) game.Rules {
+	index := slices.IndexFunc(options, func(rules game.Rules) bool {
+		return rules.Name == current.Name
+	})
+
+	return options[(index+1)%len(options)]
+}
+
+// mapOptions are the level maps, followed by the configured map and the map
+// of the editor.
+func (s *MenuStart) mapOptions() []mapOption {
+	options := []mapOption{{name: levelMapsName}}
+
+	for _, file := range []string{s.config.MapFile, defaultMapFile} {
+		if file == "" || slices.ContainsFunc(options, func(option mapOption) bool { return option.name == file }) {
+			continue
+		}
+
+		if gameMap, err := loadMapFile(file); err == nil {
+			options = append(options, mapOption{name: file, gameMap: gameMap})
+		}
+	}
+
+	return options
+}
+
+func nextMap(options []mapOption, current string) mapOption {
+	index := slices.IndexFunc(options, func(option mapOption) bool {
+		return option.name == current
+	})
+
+	return options[(index+1)%len(options)]
+}
+
+func (s *MenuStart) drawLobby(screen *ebiten.Image, face *text.GoTextFace, op *text.DrawOptions) {
+	lobby := s.client.Payload.Lobby
+
+	op.GeoM.Reset()
+	op.GeoM.Translate(360, 150)
+
+	for i, slot := range lobby.Slots {
+		line := fmt.Sprintf("Spieler %v", i+1)
+		if slot.Team != 0 {
+			line += fmt.Sprintf(" (Team %d)", slot.Team)
+		}
+
+		switch {
+		case slot.Bot:
+			line += " : Bot"
+		case !slot.Connected:
+			line += " : " + s.blink.Show("...")
+		case slot.Ready:
+			line += " : " + slot.Profile.Name + " - bereit"
+		default:
+			line += " : " + slot.Profile.Name + " - wartet"
+		}
+
+		if i == lobby.Own {
+			line += " (du)"
+		}
+
+		text.Draw(screen, line, face, op)
+		op.GeoM.Translate(0, 40)
+	}
+
+	op.GeoM.Translate(0, 20)
+	drawRules(screen, s.client.Payload.Rules, op)
+	op.GeoM.Translate(0, 20)
+	text.Draw(screen, "Karte: "+lobby.MapName, face, op)
+	op.GeoM.Translate(0, 40)
+
+	switch {
+	case !lobby.Joined():
+		text.Draw(screen, "Warte auf Spieler"+s.blink.Show("..."), face, op)
+	case !lobby.Ready():
+		text.Draw(screen, "Warte bis alle bereit sind"+s.blink.Show("..."), face, op)
+	case s.server != nil:
+		text.Draw(screen, "Alle bereit, Enter startet", face, op)
+	default:
+		text.Draw(screen, "Alle bereit, warte auf den Host"+s.blink.Show("..."), face, op)
+	}
+
+	if s.message != "" {
+		op.GeoM.Translate(0, 60)
+		text.Draw(screen, s.message, face, op)
+	}
+
+	// The rules take the room below the slots, the keys go below the menu
+	op.GeoM.Reset()
+	op.GeoM.Translate(50, 450)
+
+	help := []string{"Leertaste: Bereit", "Esc: Verlassen"}
+	if s.server != nil {
+		help = append(help,
+			"Hoch/Runter: Spieler",
+			"Links/Rechts: Bots",
+			"R: Regeln",
+			"M: Karte",
+			"Enter: Starten",
+		)
+	}
+	for _, line := range help {
+		text.Draw(screen, line, face, op)
+		op.GeoM.Translate(0, 40)
+	}
+}
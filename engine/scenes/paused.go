@@ -34,7 +34,14 @@ func (s *MenuPaused) Update() error {
 
 func (s *MenuPaused) Draw(screen *ebiten.Image) {
 	drawPausedScreen(screen)
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(color.White)
+	op.GeoM.Translate(engine.GameWidth/2-300, engine.DisplayHeight/2+30)
+	drawRules(screen, s.client.Payload.Rules, op)
+
 	drawPlayerInfo(screen, s.client.Payload)
+	drawLatency(screen, s.client.Latency())
 }
 
 func drawPausedScreen(screen *ebiten.Image) {
@@ -0,0 +1,151 @@
+package scenes
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/engine"
+	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/apfelfrisch/gosnake/game/replay"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/joelschutz/stagehand"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const replaySeekFrames = 50
+
+type ReplayViewer struct {
+	player   *replay.Player
+	paused   bool
+	lastStep time.Time
+}
+
+func NewReplayViewer(r *replay.Replay) (*ReplayViewer, error) {
+	player, err := replay.NewPlayer(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayViewer{player: player}, nil
+}
+
+func (s *ReplayViewer) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return engine.DisplayWidth, engine.DisplayHeight
+}
+
+func (s *ReplayViewer) Load(st game.GameState, sm stagehand.SceneController[game.GameState]) {
+}
+
+func (s *ReplayViewer) Unload() game.GameState {
+	return s.player.Game().State()
+}
+
+func (s *ReplayViewer) Update() error {
+	seek := 1
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		seek = replaySeekFrames
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		s.paused = !s.paused
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		s.paused = true
+		s.player.Seek(s.player.Frame() + seek)
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		s.paused = true
+		s.player.Seek(s.player.Frame() - seek)
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyHome) {
+		s.player.Seek(0)
+	}
+
+	if !s.paused && time.Since(s.lastStep) >= s.player.Game().Rules().TickDuration() {
+		s.player.Step()
+		s.lastStep = time.Now()
+	}
+
+	return nil
+}
+
+func (s *ReplayViewer) Draw(screen *ebiten.Image) {
+	g := s.player.Game()
+	players := g.Players()
+
+	drawCandies(screen, g.Candies())
+	for i, snake := range players {
+		c := color.Color(color.RGBA{30, 144, 255, 255})
+		if snake.Team != 0 {
+			c = teamColor(snake.Team, i == 0)
+		} else if i > 0 && i-1 < len(snakecolors) {
+			c = snakecolors[i-1]
+		}
+		drawSnake(screen, snake, c)
+	}
+	drawGameField(screen, g.Map().World())
+
+	pl := &payload.Payload{
+		MapLevel:  g.Level(),
+		GameState: g.State(),
+		Candies:   g.Candies(),
+		Rules:     g.Rules(),
+	}
+	if len(players) > 0 {
+		pl.Player = players[0]
+		pl.Opponents = players[1:]
+	}
+	drawPlayerInfo(screen, pl)
+
+	s.drawStatus(screen)
+}
+
+func (s *ReplayViewer) drawStatus(screen *ebiten.Image) {
+	menuFont, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatal(err)
+	}
+	face := &text.GoTextFace{
+		Source: menuFont,
+		Size:   20.0,
+	}
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(color.White)
+
+	status := "Play"
+	if s.paused {
+		status = "Pause"
+	} else if s.player.Finished() {
+		status = "Ende"
+	}
+
+	op.GeoM.Translate(playerInfoXOffset, engine.DisplayHeight-130)
+	text.Draw(screen, fmt.Sprintf("Replay: %s (%d/%d)", status, s.player.Frame(), s.player.Len()), face, op)
+	op.GeoM.Translate(0, 30)
+	text.Draw(screen, "Space: Play/Pause, Home: Start", face, op)
+	op.GeoM.Translate(0, 30)
+	text.Draw(screen, fmt.Sprintf("</>: Step, Shift+</>: %d Steps", replaySeekFrames), face, op)
+}
+
+func drawSnake(screen *ebiten.Image, snake game.Snake, c color.Color) {
+	if !snake.Alive {
+		return
+	}
+
+	for _, pos := range snake.Occupied {
+		vector.DrawFilledRect(
+			screen,
+			float32(pos.X*engine.GridSize-engine.GridSize),
+			float32(pos.Y*engine.GridSize-engine.GridSize),
+			float32(engine.GridSize),
+			float32(engine.GridSize),
+			c,
+			false,
+		)
+	}
+}
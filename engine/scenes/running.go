@@ -7,6 +7,7 @@ import (
 
 	"github.com/apfelfrisch/gosnake/engine"
 	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -41,6 +42,8 @@ func (s *GameRunning) Update() error {
 		s.client.PressKey('d')
 	} else if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
 		s.client.PressKey(' ')
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		s.client.PressKey('r')
 	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
 		s.client.PressKey('↵')
 	}
@@ -57,6 +60,7 @@ func (s *GameRunning) Draw(screen *ebiten.Image) {
 	drawSnakes(screen, &s.BaseScene)
 	drawGameField(screen, s.client.World())
 	drawPlayerInfo(screen, s.client.Payload)
+	drawLatency(screen, s.client.Latency())
 }
 
 func drawGameField(screen *ebiten.Image, world []game.FieldPos) {
@@ -93,22 +97,62 @@ var snakecolors = [4]color.Color{
 	color.RGBA{204, 0, 204, 255},
 }
 
+// teamcolors holds a bright color for the own snake and a darker one for
+// the other snakes of each team.
+var teamcolors = [4][2]color.Color{
+	{color.RGBA{30, 144, 255, 255}, color.RGBA{0, 90, 180, 255}},
+	{color.RGBA{255, 60, 60, 255}, color.RGBA{170, 0, 0, 255}},
+	{color.RGBA{120, 230, 40, 255}, color.RGBA{60, 150, 0, 255}},
+	{color.RGBA{230, 60, 230, 255}, color.RGBA{150, 0, 150, 255}},
+}
+
+func teamColor(team uint8, own bool) color.Color {
+	colors := teamcolors[int(team-1)%len(teamcolors)]
+	if own {
+		return colors[0]
+	}
+
+	return colors[1]
+}
+
+// profileColor returns the preferred color of the profile at index, if the
+// player has one.
+func profileColor(profiles []payload.Profile, index int) (color.Color, bool) {
+	if index >= len(profiles) || profiles[index].Color == 0 {
+		return nil, false
+	}
+
+	rgb := profiles[index].Color
+
+	return color.RGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 255}, true
+}
+
 func drawSnakes(screen *ebiten.Image, base *BaseScene) {
 	intermidiatPixel := 3
 
-	player := base.client.Payload.Player
+	player := base.client.PredictedPlayer()
 	if base.localPlayer.OutOfsync(player) {
 		base.localPlayer.Sync(player)
 	}
 
+	playerColor := color.Color(color.RGBA{30, 144, 255, 255})
+	if player.Team != 0 {
+		playerColor = teamColor(player.Team, true)
+	} else if c, ok := profileColor(base.client.Payload.Profiles, 0); ok {
+		playerColor = c
+	}
+
 	for _, body := range base.localPlayer.Positions(player.Direction, intermidiatPixel) {
+		if !player.Alive {
+			break
+		}
 		vector.DrawFilledRect(
 			screen,
 			body.X,
 			body.Y,
 			body.Width,
 			body.Height,
-			color.RGBA{30, 144, 255, 255},
+			playerColor,
 			false,
 		)
 	}
@@ -125,8 +169,16 @@ func drawSnakes(screen *ebiten.Image, base *BaseScene) {
 			base.localOpponents[i].Sync(opp)
 		}
 
+		if !opp.Alive {
+			continue
+		}
+
 		for _, body := range base.localOpponents[i].Positions(opp.Direction, intermidiatPixel) {
-			if i >= 0 && i < len(snakecolors) {
+			if opp.Team != 0 {
+				c = teamColor(opp.Team, false)
+			} else if pc, ok := profileColor(base.client.Payload.Profiles, i+1); ok {
+				c = pc
+			} else if i >= 0 && i < len(snakecolors) {
 				c = snakecolors[i]
 			} else {
 				c = color.RGBA{30, 144, 255, 255}
@@ -157,6 +209,18 @@ func drawCandies(screen *ebiten.Image, candies []game.Candy) {
 		)
 	}
 
+	drawRing := func(pos game.Position, c color.Color) {
+		vector.StrokeCircle(
+			screen,
+			float32(pos.X*engine.GridSize-engine.GridSize/2),
+			float32(pos.Y*engine.GridSize-engine.GridSize/2),
+			float32(engine.GridSize)/4,
+			2,
+			c,
+			true,
+		)
+	}
+
 	for _, candy := range candies {
 		switch candy.CandyTpe {
 		case game.CandyGrow:
@@ -165,6 +229,18 @@ func drawCandies(screen *ebiten.Image, candies []game.Candy) {
 			drawCircle(candy.Position, color.RGBA{202, 255, 112, 255})
 		case game.CandyDash:
 			drawCircle(candy.Position, color.RGBA{85, 26, 139, 255})
+		case game.CandyShield:
+			drawCircle(candy.Position, color.RGBA{70, 130, 180, 255})
+			drawRing(candy.Position, color.RGBA{248, 248, 255, 255})
+		case game.CandyGhost:
+			drawCircle(candy.Position, color.RGBA{248, 248, 255, 90})
+			drawRing(candy.Position, color.RGBA{248, 248, 255, 255})
+		case game.CandyMagnet:
+			drawCircle(candy.Position, color.RGBA{220, 20, 60, 255})
+			drawRing(candy.Position, color.RGBA{192, 192, 192, 255})
+		case game.CandyReverse:
+			drawCircle(candy.Position, color.RGBA{255, 165, 0, 255})
+			drawRing(candy.Position, color.RGBA{0, 0, 0, 255})
 		default:
 			panic(fmt.Sprintf("unexpected game.CandyTpe: %#v", candy.CandyTpe))
 		}
@@ -0,0 +1,243 @@
+package scenes
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+
+	"github.com/apfelfrisch/gosnake/engine"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	cameraSpeed = 10.0
+	cameraZoom  = 1.25
+	maxZoom     = 4.0
+)
+
+// camera shows a part of the game field, either moved freely or following
+// a player.
+type camera struct {
+	// x and y are the center of the view in field pixels.
+	x, y float64
+	zoom float64
+	// follow is the followed player slot, -1 for the free camera.
+	follow int
+}
+
+func newCamera() camera {
+	return camera{
+		x:      engine.GameWidth / 2,
+		y:      engine.GameHeight / 2,
+		zoom:   1,
+		follow: -1,
+	}
+}
+
+// clamp keeps the view inside the game field.
+func (c *camera) clamp() {
+	c.zoom = max(1, min(c.zoom, maxZoom))
+
+	halfWidth := engine.GameWidth / 2 / c.zoom
+	halfHeight := engine.GameHeight / 2 / c.zoom
+	c.x = max(halfWidth, min(c.x, engine.GameWidth-halfWidth))
+	c.y = max(halfHeight, min(c.y, engine.GameHeight-halfHeight))
+}
+
+type SpectatorView struct {
+	BaseScene
+	menu   *MenuStart
+	camera camera
+	world  *ebiten.Image
+}
+
+func NewSpectatorView(menu *MenuStart) *SpectatorView {
+	return &SpectatorView{
+		BaseScene: menu.BaseScene,
+		menu:      menu,
+		camera:    newCamera(),
+		world:     ebiten.NewImage(engine.GameWidth, engine.GameHeight),
+	}
+}
+
+func (s *SpectatorView) Update() error {
+	s.client.UpdatePayload()
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.menu.leave()
+		s.sm.SwitchTo(s.menu)
+		return nil
+	}
+
+	snakes := s.client.Payload.Snakes
+	for i, key := range []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5, ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9} {
+		if inpututil.IsKeyJustPressed(key) && i < len(snakes) {
+			s.camera.follow = i
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.Key0) {
+		s.camera = newCamera()
+	}
+
+	var dx, dy float64
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		dx -= cameraSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		dx += cameraSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		dy -= cameraSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		dy += cameraSpeed
+	}
+	if dx != 0 || dy != 0 {
+		// Moving the camera stops following
+		s.camera.follow = -1
+		s.camera.x += dx / s.camera.zoom
+		s.camera.y += dy / s.camera.zoom
+	}
+
+	_, wheel := ebiten.Wheel()
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyKPAdd) || wheel > 0 {
+		s.camera.zoom *= cameraZoom
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyKPSubtract) || wheel < 0 {
+		s.camera.zoom /= cameraZoom
+	}
+
+	if s.camera.follow >= len(snakes) {
+		s.camera.follow = -1
+	}
+	if s.camera.follow != -1 && len(snakes[s.camera.follow].Occupied) > 0 {
+		head := snakes[s.camera.follow].Head()
+		s.camera.x = float64(head.X*engine.GridSize - engine.GridSize/2)
+		s.camera.y = float64(head.Y*engine.GridSize - engine.GridSize/2)
+	}
+
+	s.camera.clamp()
+
+	return nil
+}
+
+func (s *SpectatorView) Draw(screen *ebiten.Image) {
+	pl := s.client.Payload
+
+	if pl.Lobby != nil {
+		drawSpectatorLobby(screen, pl.Lobby)
+		return
+	}
+
+	s.world.Clear()
+	drawCandies(s.world, pl.Candies)
+	for i, snake := range pl.Snakes {
+		drawSnake(s.world, snake, spectatorColor(pl, i, i == s.camera.follow))
+	}
+	drawGameField(s.world, s.client.World())
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-s.camera.x, -s.camera.y)
+	op.GeoM.Scale(s.camera.zoom, s.camera.zoom)
+	op.GeoM.Translate(engine.GameWidth/2, engine.GameHeight/2)
+	screen.SubImage(image.Rect(0, 0, engine.GameWidth, engine.GameHeight)).(*ebiten.Image).DrawImage(s.world, op)
+
+	info := &payload.Payload{
+		GameState: pl.GameState,
+		Rules:     pl.Rules,
+		Profiles:  pl.Profiles,
+	}
+	if len(pl.Snakes) > 0 {
+		info.Player = pl.Snakes[0]
+		info.Opponents = pl.Snakes[1:]
+	}
+	drawPlayerInfo(screen, info)
+
+	s.drawStatus(screen)
+}
+
+func (s *SpectatorView) drawStatus(screen *ebiten.Image) {
+	face := spectatorFace(20)
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(color.White)
+
+	status := "freie Kamera"
+	if s.camera.follow != -1 {
+		status = fmt.Sprintf("folgt Spieler %d", s.camera.follow+1)
+		if profiles := s.client.Payload.Profiles; s.camera.follow < len(profiles) && profiles[s.camera.follow].Name != "" {
+			status = "folgt " + profiles[s.camera.follow].Name
+		}
+	}
+
+	op.GeoM.Translate(playerInfoXOffset, engine.DisplayHeight-160)
+	text.Draw(screen, "Zuschauer: "+status, face, op)
+	for _, help := range []string{
+		"1-9: Spieler folgen, 0: Freie Kamera",
+		"Pfeile: Bewegen, +/-: Zoom",
+		"Esc: Verlassen",
+	} {
+		op.GeoM.Translate(0, 30)
+		text.Draw(screen, help, face, op)
+	}
+}
+
+func drawSpectatorLobby(screen *ebiten.Image, lobby *payload.Lobby) {
+	face := spectatorFace(30)
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(color.White)
+	op.GeoM.Translate(50, 50)
+
+	text.Draw(screen, "Warte auf Spielbeginn - Karte: "+lobby.MapName, face, op)
+
+	for i, slot := range lobby.Slots {
+		op.GeoM.Translate(0, 40)
+
+		name := slot.Profile.Name
+		switch {
+		case slot.Bot:
+		case !slot.Connected:
+			name = "..."
+		case slot.Ready:
+			name += " - bereit"
+		default:
+			name += " - wartet"
+		}
+		text.Draw(screen, fmt.Sprintf("Spieler %d : %s", i+1, name), face, op)
+	}
+}
+
+// spectatorColor is the color of the snake in slot index, the followed snake
+// is highlighted in team games.
+func spectatorColor(pl *payload.Payload, index int, followed bool) color.Color {
+	snake := pl.Snakes[index]
+	if snake.Team != 0 {
+		return teamColor(snake.Team, followed)
+	}
+	if c, ok := profileColor(pl.Profiles, index); ok {
+		return c
+	}
+	if index > 0 && index-1 < len(snakecolors) {
+		return snakecolors[index-1]
+	}
+
+	return color.RGBA{30, 144, 255, 255}
+}
+
+func spectatorFace(size float64) *text.GoTextFace {
+	menuFont, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return &text.GoTextFace{
+		Source: menuFont,
+		Size:   size,
+	}
+}
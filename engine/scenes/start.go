@@ -3,15 +3,22 @@ package scenes
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"log"
+	"net"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/apfelfrisch/gosnake/engine"
 	"github.com/apfelfrisch/gosnake/game"
+	netClient "github.com/apfelfrisch/gosnake/game/network/client"
+	"github.com/apfelfrisch/gosnake/game/network/discovery"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
 	netServer "github.com/apfelfrisch/gosnake/game/network/server"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -25,19 +32,28 @@ const (
 	singleplayer gametype = 0
 	client       gametype = 1
 	server       gametype = 2
+	editor       gametype = 3
+	spectator    gametype = 4
 )
 
+const gametypeCount = 5
+
 func (gt gametype) prev() gametype {
 	index := int(gt) - 1
 	if index < 0 {
-		index = 2
+		index = gametypeCount - 1
 	}
 	return gametype(index)
 }
 
+// joins reports if the gametype connects to a server by address.
+func (gt gametype) joins() bool {
+	return gt == client || gt == spectator
+}
+
 func (gt gametype) next() gametype {
 	index := int(gt) + 1
-	if index > 2 {
+	if index >= gametypeCount {
 		index = 0
 	}
 	return gametype(index)
@@ -72,22 +88,59 @@ func (c *blink) Show(text string) string {
 
 type MenuStart struct {
 	BaseScene
-	gametype    gametype
-	connection  connState
-	playerCount int
-	blink       blink
-	serverAddr  string
-	server      *netServer.GameServer
-	ctx         context.Context
-	cancle      context.CancelFunc
+	gametype     gametype
+	connection   connState
+	playerCount  int
+	botCount     int
+	blink        blink
+	serverAddr   string
+	network      string
+	browser      *discovery.Browser
+	servers      []discovery.Server
+	selected     int
+	browseFailed bool
+	rejection    string
+	message      string
+	config       Config
+	server       *netServer.GameServer
+	ctx          context.Context
+	cancle       context.CancelFunc
 }
 
-func New() *MenuStart {
+type Config struct {
+	// ReplayDir enables match recording for hosted games.
+	ReplayDir string
+	// MapFile is the custom map used for hosted games and by the map editor.
+	MapFile string
+	// Rules for hosted games, the classic rules if nil.
+	Rules *game.Rules
+	// Profile is sent to the server when joining a game.
+	Profile payload.Profile
+	// ListenAddr is the address hosted games listen on, port 0 picks a free
+	// port.
+	ListenAddr string
+	// Network is the preselected network for hosting and joining games,
+	// "udp" if empty.
+	Network string
+	// AllowedOrigins are the hosts of the pages browser clients may join
+	// hosted games from.
+	AllowedOrigins []string
+}
+
+func New(config Config) *MenuStart {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	network := config.Network
+	if network == "" {
+		network = netClient.Networks[0]
+	}
+
 	return &MenuStart{
-		ctx:    ctx,
-		cancle: cancel,
+		ctx:      ctx,
+		cancle:   cancel,
+		config:   config,
+		network:  network,
+		selected: -1,
 		BaseScene: BaseScene{
 			bounds: image.Rectangle{},
 			localPlayer: engine.ClientSnake{
@@ -106,6 +159,16 @@ func (s *MenuStart) Update() error {
 		s.client.UpdatePayload()
 		s.localPlayer.Sync(s.client.Payload.Player)
 
+		if s.client.Payload.Spectator {
+			s.sm.SwitchTo(NewSpectatorView(s))
+			return nil
+		}
+
+		if s.client.Payload.Lobby != nil {
+			s.updateLobby()
+			return nil
+		}
+
 		if s.client.Payload.GameState == game.Ongoing {
 			s.sm.SwitchTo(&GameRunning{BaseScene: s.BaseScene})
 		}
@@ -120,7 +183,9 @@ func (s *MenuStart) Update() error {
 		return nil
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) && s.gametype == editor {
+		s.sm.SwitchTo(NewMapEditor(s))
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
 		s.connect()
 	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
 		s.gametype = s.gametype.prev()
@@ -128,15 +193,19 @@ func (s *MenuStart) Update() error {
 		s.gametype = s.gametype.next()
 	}
 
-	if s.gametype == client {
+	if s.gametype.joins() {
+		s.updateServerList()
+
 		for _, char := range ebiten.AppendInputChars(nil) {
-			if char >= '0' && char <= '9' || char == '.' {
+			if isAddrChar(char) {
 				s.serverAddr += string(char)
+				s.selected = -1
 			}
 		}
 
 		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(s.serverAddr) > 0 {
 			s.serverAddr = s.serverAddr[:len(s.serverAddr)-1]
+			s.selected = -1
 		}
 	} else if s.gametype == server {
 		if s.playerCount < 2 {
@@ -150,9 +219,40 @@ func (s *MenuStart) Update() error {
 		}
 	}
 
+	if s.gametype.joins() || s.gametype == server {
+		if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+			s.network = nextNetwork(s.network)
+		}
+	}
+
+	if s.gametype == singleplayer || s.gametype == server {
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+			s.botCount--
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+			s.botCount++
+		}
+		s.botCount = max(0, min(s.botCount, s.maxBots()))
+	}
+
 	return nil
 }
 
+// nextNetwork returns the network after network in netClient.Networks.
+func nextNetwork(network string) string {
+	index := slices.Index(netClient.Networks, network)
+
+	return netClient.Networks[(index+1)%len(netClient.Networks)]
+}
+
+// maxBots keeps at least one slot free for the local player.
+func (s *MenuStart) maxBots() int {
+	if s.gametype == server {
+		return s.playerCount - 1
+	}
+
+	return 8
+}
+
 func (s *MenuStart) Draw(screen *ebiten.Image) {
 	menuFont, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
 	if err != nil {
@@ -182,10 +282,25 @@ func (s *MenuStart) drawLeftMenu(screen *ebiten.Image, face *text.GoTextFace, op
 	op.GeoM.Translate(0, 50)
 	text.Draw(screen, "Server", face, op)
 
+	op.GeoM.Translate(0, 50)
+	text.Draw(screen, "Karten-Editor", face, op)
+
+	op.GeoM.Translate(0, 50)
+	text.Draw(screen, "Zuschauer", face, op)
+
+	if s.gametype.joins() || s.gametype == server {
+		op.GeoM.Translate(0, 100)
+		text.Draw(screen, "Netzwerk (F2): "+strings.ToUpper(s.network), face, op)
+		op.GeoM.Translate(0, -100)
+	}
+	op.GeoM.Translate(0, -100)
+
 	switch s.gametype {
 	case singleplayer:
 		op.GeoM.Translate(-40, -100)
 		text.Draw(screen, "->", face, op)
+		op.GeoM.Translate(350, 0)
+		text.Draw(screen, fmt.Sprintf("Bots: < %d >", s.botCount), face, op)
 	case client:
 		op.GeoM.Translate(-40, -50)
 		text.Draw(screen, "->", face, op)
@@ -204,73 +319,152 @@ func (s *MenuStart) drawLeftMenu(screen *ebiten.Image, face *text.GoTextFace, op
 		} else {
 			text.Draw(screen, "Anzahl Spieler: "+s.blink.Show(strconv.Itoa(s.playerCount)), face, op)
 		}
+		op.GeoM.Translate(0, 50)
+		text.Draw(screen, fmt.Sprintf("Bots: < %d >", s.botCount), face, op)
+	case editor:
+		op.GeoM.Translate(-40, 50)
+		text.Draw(screen, "->", face, op)
+	case spectator:
+		op.GeoM.Translate(-40, 100)
+		text.Draw(screen, "->", face, op)
+		op.GeoM.Translate(350, -200)
+		if s.connection == connPending {
+			text.Draw(screen, "Server Adresse: "+s.serverAddr, face, op)
+		} else {
+			text.Draw(screen, "Server Adresse: "+s.serverAddr+s.blink.Show("|"), face, op)
+		}
 	default:
 		panic("unexpected scenes.gametype")
 	}
 }
 
 func (s *MenuStart) drawContextMenu(screen *ebiten.Image, face *text.GoTextFace, op *text.DrawOptions) {
-	if s.gametype == singleplayer {
+	if s.gametype == singleplayer || s.gametype == editor {
+		return
+	}
+
+	if s.client != nil && s.client.Payload.Lobby != nil {
+		s.drawLobby(screen, face, op)
 		return
 	}
 
-	if s.gametype == client {
+	if s.gametype.joins() {
 		op.GeoM.Reset()
 		op.GeoM.Translate(360, 150)
 		if s.connection == connPending {
 			text.Draw(screen, "Verbinde"+s.blink.Show("..."), face, op)
 		} else if s.connection == connFinished {
 			text.Draw(screen, "Verbunden", face, op)
+		} else if s.rejection != "" {
+			text.Draw(screen, "Abgelehnt: "+s.rejection, face, op)
+		} else if s.message != "" {
+			text.Draw(screen, s.message, face, op)
 		}
 
-		return
-	}
-
-	if s.server == nil || !s.server.IsListining() {
-		return
-	}
-
-	op.GeoM.Reset()
-	op.GeoM.Translate(360, 150)
-
-	clients := s.server.Clients()
-	for i := 1; i <= s.playerCount; i++ {
-		if len(clients) >= i {
-			text.Draw(screen, fmt.Sprintf("Spieler %v : verbunden", i), face, op)
-		} else {
-			text.Draw(screen, fmt.Sprintf("Spieler %v : ", i)+s.blink.Show("..."), face, op)
-		}
-
-		op.GeoM.Translate(0, 40)
+		s.drawServerList(screen, face, op)
 	}
 }
 
 func (s *MenuStart) connect() {
-	connClient := func() {
+	connectTo := engine.ConnectClient
+	if s.gametype == spectator {
+		connectTo = engine.ConnectSpectator
+	}
+
+	connClient := func(network, addr string) {
 		var err error
-		s.client, err = engine.ConnectClient(s.ctx, s.serverAddr+":1200")
+		s.client, err = connectTo(s.ctx, network, addr, s.config.Profile)
 		if err != nil {
+			var rejected *netClient.RejectedError
+			if errors.As(err, &rejected) {
+				s.rejection = rejected.Reason
+			} else if !errors.Is(err, context.Canceled) {
+				s.message = "Verbindung fehlgeschlagen: " + err.Error()
+			}
 			s.connection = connClosed
 			s.ctx, s.cancle = context.WithCancel(context.Background())
 			return
 		}
-		s.client.PressKey('↵')
+		s.connection = connFinished
+		s.stopBrowsing()
 	}
 
+	s.rejection = ""
+	s.message = ""
+
 	switch s.gametype {
-	case client:
+	case client, spectator:
 		s.connection = connPending
-		go connClient()
+		go connClient(s.network, s.joinAddr())
 	case server:
+		opts := s.serverOptions()
+		opts.Name = s.serverName()
+		opts.Network = s.network
+		if !s.host(s.playerCount, s.config.ListenAddr, opts) {
+			return
+		}
 		s.connection = connPending
-		s.server = engine.BuildServer(s.playerCount, ":1200")
-		s.server.RunBackground(s.ctx)
-		go connClient()
+		go connClient(s.network, localAddr(s.server.Addr()))
 	case singleplayer:
-		s.server = engine.BuildServer(1, ":1200")
-		s.server.RunBackground(s.ctx)
-		connClient()
+		opts := s.serverOptions()
+		opts.AutoStart = true
+		if !s.host(1+s.botCount, "127.0.0.1:0", opts) {
+			return
+		}
+		connClient("udp", localAddr(s.server.Addr()))
 	default:
 		panic(fmt.Sprintf("unexpected scenes.gametype: %#v", s.gametype))
 	}
 }
+
+// host starts a server for the local player and reports if it is running.
+func (s *MenuStart) host(playerCount int, addr string, opts engine.ServerOptions) bool {
+	var err error
+	s.server, err = engine.BuildServer(playerCount, addr, opts)
+	if err == nil {
+		err = s.server.RunBackground(s.ctx)
+	}
+	if err != nil {
+		s.message = "Server konnte nicht starten: " + err.Error()
+		s.server = nil
+		return false
+	}
+
+	return true
+}
+
+// localAddr is where a client on this machine reaches a server bound to addr.
+func localAddr(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+func (s *MenuStart) serverOptions() engine.ServerOptions {
+	opts := engine.ServerOptions{
+		ReplayDir:      s.config.ReplayDir,
+		Bots:           s.botCount,
+		Rules:          s.config.Rules,
+		AllowedOrigins: s.config.AllowedOrigins,
+	}
+
+	opts.MapName = levelMapsName
+	if s.config.MapFile != "" {
+		customMap, err := loadMapFile(s.config.MapFile)
+		if err != nil {
+			log.Println("Could not load custom map:", err)
+		} else {
+			opts.Map = customMap
+			opts.MapName = s.config.MapFile
+		}
+	}
+
+	return opts
+}
@@ -0,0 +1,8 @@
+package maps
+
+import (
+	"embed"
+)
+
+//go:embed *.txt
+var Files embed.FS
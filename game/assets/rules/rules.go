@@ -0,0 +1,8 @@
+package rules
+
+import (
+	"embed"
+)
+
+//go:embed *.json
+var Files embed.FS
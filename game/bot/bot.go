@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+// Bot controls a player slot of a game. Bots answer with the same inputs a
+// client would send, so their moves go through game.Step and show up in
+// recordings like every other player.
+type Bot interface {
+	Input(g *game.Game, playerIndex int) game.Input
+}
+
+type Kind int
+
+const (
+	KindGreedy Kind = iota
+	KindSurvival
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindGreedy:
+		return "Greedy"
+	case KindSurvival:
+		return "Survival"
+	}
+
+	return "Unkown"
+}
+
+func New(kind Kind) Bot {
+	switch kind {
+	case KindSurvival:
+		return Survival{}
+	default:
+		return Greedy{}
+	}
+}
+
+var directions = []game.Direction{game.North, game.East, game.South, game.West}
+
+var directionInputs = map[game.Direction]game.Input{
+	game.North: game.InputNorth,
+	game.East:  game.InputEast,
+	game.South: game.InputSouth,
+	game.West:  game.InputWest,
+}
+
+// grid is a snapshot of game.Game.Field for one player, taken once per step.
+type grid struct {
+	width  uint16
+	height uint16
+	fields map[game.Position]game.Field
+}
+
+func newGrid(g *game.Game, playerIndex int) grid {
+	gr := grid{
+		width:  g.Width(),
+		height: g.Height(),
+		fields: make(map[game.Position]game.Field, int(g.Width())*int(g.Height())),
+	}
+
+	for y := uint16(1); y <= g.Height(); y++ {
+		for x := uint16(1); x <= g.Width(); x++ {
+			pos := game.Position{Y: y, X: x}
+			gr.fields[pos] = g.Field(playerIndex, pos)
+		}
+	}
+
+	return gr
+}
+
+func (gr grid) isFree(pos game.Position) bool {
+	field, ok := gr.fields[pos]
+
+	return ok && (field == game.FieldEmpty || field == game.FieldCandy)
+}
+
+// floodFill counts the free fields reachable from start.
+func (gr grid) floodFill(start game.Position) int {
+	if !gr.isFree(start) {
+		return 0
+	}
+
+	visited := map[game.Position]bool{start: true}
+	queue := []game.Position{start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range directions {
+			next := pos.Move(dir)
+			if !visited[next] && gr.isFree(next) {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return len(visited)
+}
+
+// nextHead returns the field the snake moves to in the coming tick. A new
+// direction only takes effect on the move after that one.
+func nextHead(snake game.Snake) game.Position {
+	return snake.Head().Move(snake.Direction)
+}
+
+// turns returns the directions the snake may choose for its next move.
+func turns(snake game.Snake) []game.Direction {
+	result := make([]game.Direction, 0, len(directions)-1)
+	for _, dir := range directions {
+		if dir != snake.Direction.Opposite() {
+			result = append(result, dir)
+		}
+	}
+
+	return result
+}
+
+func turnInput(snake game.Snake, dir game.Direction) game.Input {
+	if dir == snake.NewDirection {
+		return game.InputNone
+	}
+
+	return directionInputs[dir]
+}
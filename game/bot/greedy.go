@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+// Greedy takes the shortest path to the nearest candy and dashes when a
+// candy lies straight ahead. Without a reachable candy it plays like Survival.
+type Greedy struct{}
+
+func (Greedy) Input(g *game.Game, playerIndex int) game.Input {
+	if g.State() != game.Ongoing {
+		return game.InputNone
+	}
+
+	snake := g.Players()[playerIndex]
+	if !snake.Alive {
+		return game.InputNone
+	}
+	gr := newGrid(g, playerIndex)
+
+	if canDashToCandy(gr, snake, g.Rules().DashLength) {
+		return game.InputDash
+	}
+
+	dir, ok := pathToCandy(gr, snake)
+	if !ok || gr.floodFill(nextHead(snake).Move(dir)) < len(snake.Occupied) {
+		// Never follow a candy into a dead end
+		dir = safestTurn(gr, snake)
+	}
+
+	return turnInput(snake, dir)
+}
+
+// pathToCandy runs a breadth-first search from the next head position and
+// returns the first turn of the shortest path to any candy.
+func pathToCandy(gr grid, snake game.Snake) (game.Direction, bool) {
+	head := nextHead(snake)
+	if !gr.isFree(head) {
+		return snake.Direction, false
+	}
+
+	type step struct {
+		pos   game.Position
+		first game.Direction
+	}
+
+	visited := map[game.Position]bool{head: true}
+	var queue []step
+	for _, dir := range turns(snake) {
+		if next := head.Move(dir); gr.isFree(next) && !visited[next] {
+			visited[next] = true
+			queue = append(queue, step{next, dir})
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if gr.fields[current.pos] == game.FieldCandy {
+			return current.first, true
+		}
+
+		for _, dir := range directions {
+			if next := current.pos.Move(dir); gr.isFree(next) && !visited[next] {
+				visited[next] = true
+				queue = append(queue, step{next, current.first})
+			}
+		}
+	}
+
+	return snake.Direction, false
+}
+
+func canDashToCandy(gr grid, snake game.Snake, dashLength uint8) bool {
+	if snake.Perks.Get(game.PerkTypeDash).Usages == 0 || snake.NewDirection != snake.Direction {
+		return false
+	}
+
+	pos := snake.Head()
+	foundCandy := false
+	for i := uint8(0); i <= dashLength; i++ {
+		pos = pos.Move(snake.Direction)
+		if !gr.isFree(pos) {
+			return false
+		}
+		if gr.fields[pos] == game.FieldCandy {
+			foundCandy = true
+		}
+	}
+
+	return foundCandy
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+// Survival ignores candies and always turns towards the largest free area.
+type Survival struct{}
+
+func (Survival) Input(g *game.Game, playerIndex int) game.Input {
+	if g.State() != game.Ongoing {
+		return game.InputNone
+	}
+
+	snake := g.Players()[playerIndex]
+	if !snake.Alive {
+		return game.InputNone
+	}
+
+	return turnInput(snake, safestTurn(newGrid(g, playerIndex), snake))
+}
+
+// safestTurn returns the direction with the most reachable free fields.
+func safestTurn(gr grid, snake game.Snake) game.Direction {
+	head := nextHead(snake)
+
+	best, bestSpace := snake.Direction, -1
+	for _, dir := range turns(snake) {
+		if space := gr.floodFill(head.Move(dir)); space > bestSpace {
+			best, bestSpace = dir, space
+		}
+	}
+
+	return best
+}
@@ -6,8 +6,38 @@ const (
 	CandyGrow     CandyTpe = 0
 	CandyWalkWall CandyTpe = 1
 	CandyDash     CandyTpe = 2
+	CandyShield   CandyTpe = 3
+	CandyGhost    CandyTpe = 4
+	CandyMagnet   CandyTpe = 5
+	CandyReverse  CandyTpe = 6
 )
 
+// perkCandies lists the perk candies in spawn order. Rarity multiplies
+// the perk spawn odds of the rules, the stronger perks spawn less often.
+var perkCandies = []struct {
+	candy  CandyTpe
+	perk   PerkType
+	rarity uint32
+}{
+	{CandyWalkWall, PerkTypeWalkWall, 1},
+	{CandyDash, PerkTypeDash, 1},
+	{CandyShield, PerkTypeShield, 3},
+	{CandyGhost, PerkTypeGhost, 2},
+	{CandyMagnet, PerkTypeMagnet, 2},
+	{CandyReverse, PerkTypeReverse, 2},
+}
+
+// Perk returns the perk a candy gives, ok is false for grow candies.
+func (ct CandyTpe) Perk() (PerkType, bool) {
+	for _, pc := range perkCandies {
+		if pc.candy == ct {
+			return pc.perk, true
+		}
+	}
+
+	return 0, false
+}
+
 type Candy struct {
 	CandyTpe
 	Position
@@ -0,0 +1,343 @@
+package game
+
+// collisions checks the heads of all moved players at once, so the outcome
+// of a tick does not depend on the player order. prev holds the moved
+// players before they moved.
+func (game *Game) collisions(moved []int, prev map[int]Snake) []PlayerDied {
+	var deaths []PlayerDied
+	dead := make(map[int]bool)
+
+	die := func(playerIndex int, cause DeathCause, opponent int) {
+		if dead[playerIndex] {
+			return
+		}
+		dead[playerIndex] = true
+		deaths = append(deaths, PlayerDied{
+			Player:   playerIndex,
+			Cause:    cause,
+			Opponent: opponent,
+			Position: game.players[playerIndex].Head(),
+		})
+	}
+
+	for _, index := range moved {
+		player := &game.players[index]
+
+		if game.gameMap.IsWall(player.Head()) {
+			die(index, CauseWall, NoOpponent)
+		} else if collision := player.Head().getCollision(player.body()); collision != nil {
+			die(index, CauseSelf, NoOpponent)
+		}
+	}
+
+	headOns := make(map[[2]int]bool)
+	for _, index := range moved {
+		for other := range game.players {
+			if !game.canCollide(index, other) || headOns[[2]int{other, index}] || !game.isHeadOn(index, other, prev) {
+				continue
+			}
+			headOns[[2]int{index, other}] = true
+
+			switch game.headOnWinner(index, other) {
+			case index:
+				die(other, CauseHeadOn, index)
+			case other:
+				die(index, CauseHeadOn, other)
+			default:
+				die(index, CauseHeadOn, other)
+				die(other, CauseHeadOn, index)
+			}
+		}
+	}
+
+	for _, index := range moved {
+		head := game.players[index].Head()
+
+		for other := range game.players {
+			if !game.canCollide(index, other) || headOns[[2]int{index, other}] || headOns[[2]int{other, index}] {
+				continue
+			}
+			if collision := head.getCollision(game.players[other].Occupied); collision != nil {
+				die(index, CauseOpponent, other)
+				break
+			}
+		}
+	}
+
+	return deaths
+}
+
+// canCollide reports if a player can crash into another player. Dead
+// snakes, teammates without friendly fire and ghosts are passed through.
+func (game *Game) canCollide(index, other int) bool {
+	if index == other || !game.players[other].Alive || game.isFriendly(index, other) {
+		return false
+	}
+
+	return !game.players[index].Perks.Active(PerkTypeGhost) && !game.players[other].Perks.Active(PerkTypeGhost)
+}
+
+// isHeadOn reports if both heads ended on the same field, or if both snakes
+// swapped their head fields.
+func (game *Game) isHeadOn(index, other int, prev map[int]Snake) bool {
+	head, otherHead := game.players[index].Head(), game.players[other].Head()
+	if head == otherHead {
+		return true
+	}
+
+	prevSnake, ok := prev[index]
+	otherPrevSnake, otherOk := prev[other]
+
+	return ok && otherOk && head == otherPrevSnake.Head() && otherHead == prevSnake.Head()
+}
+
+// useShields saves every died player with a shield by moving the snake back
+// to where it was before the tick. It returns the remaining deaths and the
+// saved players.
+func (game *Game) useShields(deaths []PlayerDied, prev map[int]Snake) ([]PlayerDied, []int) {
+	var remaining []PlayerDied
+	var shielded []int
+	for _, death := range deaths {
+		player := &game.players[death.Player]
+		prevSnake, ok := prev[death.Player]
+		if !ok || !player.Perks.use(PerkTypeShield) {
+			remaining = append(remaining, death)
+			continue
+		}
+
+		player.Occupied = prevSnake.Occupied
+		player.grows = prevSnake.grows
+		game.dodge(death.Player, prevSnake.Direction)
+		game.usedPerk(death.Player, PerkTypeShield)
+		shielded = append(shielded, death.Player)
+	}
+
+	return remaining, shielded
+}
+
+// dodge turns a shielded snake away from the field it crashed into, so it
+// does not crash again with its next move. The players own turn is preferred.
+func (game *Game) dodge(playerIndex int, crashed Direction) {
+	player := &game.players[playerIndex]
+
+	candidates := []Direction{player.NewDirection}
+	for _, dir := range []Direction{North, East, South, West} {
+		if dir != crashed && dir != crashed.Opposite() {
+			candidates = append(candidates, dir)
+		}
+	}
+
+	for _, dir := range candidates {
+		next := player.Head().Move(dir)
+		if dir != crashed && dir != crashed.Opposite() && !game.gameMap.IsWall(next) && !game.isOccupied(next) {
+			player.Direction = dir
+			player.NewDirection = dir
+			return
+		}
+	}
+}
+
+// magnetRange is the distance in fields a magnet pulls candies from.
+const magnetRange = 5
+
+// pullCandies moves every candy in magnet range one field towards the head
+// of the player.
+func (game *Game) pullCandies(playerIndex int) {
+	head := game.players[playerIndex].Head()
+
+	for i, candy := range game.candies {
+		dx, dy := int(head.X)-int(candy.X), int(head.Y)-int(candy.Y)
+		if abs(dx)+abs(dy) > magnetRange || (dx == 0 && dy == 0) {
+			continue
+		}
+
+		next := candy.Position
+		if abs(dx) >= abs(dy) {
+			next.X = uint16(int(next.X) + sign(dx))
+		} else {
+			next.Y = uint16(int(next.Y) + sign(dy))
+		}
+
+		if next == head || (!game.gameMap.IsWall(next) && !game.isOccupied(next)) {
+			game.candies[i].Position = next
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// headOnWinner returns the player surviving a head-on collision,
+// or NoOpponent if both die.
+func (game *Game) headOnWinner(index, other int) int {
+	if game.rules.HeadOn != HeadOnLongerWins {
+		return NoOpponent
+	}
+
+	length, otherLength := len(game.players[index].Occupied), len(game.players[other].Occupied)
+	switch {
+	case length > otherLength:
+		return index
+	case otherLength > length:
+		return other
+	default:
+		return NoOpponent
+	}
+}
+
+// kill takes a life from every died player, teams lose a shared life. In
+// classic mode the round ends with the first death and the game with the
+// last life. In battle royale mode the round ends when one snake or team is
+// left, and the game when at most one has lives left.
+func (game *Game) kill(deaths []PlayerDied) {
+	for _, death := range deaths {
+		player := &game.players[death.Player]
+
+		for _, index := range game.teammates(death.Player) {
+			if game.players[index].Lives > 0 {
+				game.players[index].Lives -= 1
+			}
+		}
+		switch {
+		case game.rules.IsBattleRoyale():
+			player.Alive = false
+		case player.Lives == 0:
+			game.state = GameFinished
+		case game.state != GameFinished:
+			game.state = RoundFinished
+		}
+
+		game.events = append(game.events, death)
+	}
+
+	if len(deaths) == 0 || game.state != Ongoing || !game.rules.IsBattleRoyale() {
+		return
+	}
+
+	if game.lastSnakeStanding(func(player Snake) bool { return player.Lives > 0 }) {
+		game.state = GameFinished
+	} else if game.lastSnakeStanding(func(player Snake) bool { return player.Alive }) {
+		game.state = RoundFinished
+	}
+}
+
+// lastSnakeStanding reports if at most one snake or team is left with a
+// snake that matches, none if the game has only one.
+func (game *Game) lastSnakeStanding(match func(player Snake) bool) bool {
+	sides, left := make(map[int]bool), make(map[int]bool)
+	for index, player := range game.players {
+		side := int(player.Team)
+		if side == 0 {
+			side = -index - 1
+		}
+
+		sides[side] = true
+		if match(player) {
+			left[side] = true
+		}
+	}
+
+	return len(left) == 0 || (len(left) == 1 && len(sides) > 1)
+}
+
+// shrinkMapMinSize is the smallest open area a battle royale map shrinks to.
+const shrinkMapMinSize = 6
+
+// shrinkMap adds a ring of walls inside the current walls. Snakes caught by
+// the new walls die, candies are moved to a free field.
+func (game *Game) shrinkMap() []PlayerDied {
+	if game.shrink >= game.gameMap.MaxShrink(shrinkMapMinSize) {
+		return nil
+	}
+
+	game.shrink++
+	game.gameMap.Shrink(game.shrink)
+
+	for i := len(game.candies) - 1; i >= 0; i-- {
+		if !game.gameMap.IsWall(game.candies[i].Position) {
+			continue
+		}
+
+		if game.candies[i].CandyTpe == CandyGrow {
+			game.candies[i].Position = game.candyPosition()
+		} else {
+			game.candies = append(game.candies[:i], game.candies[i+1:]...)
+		}
+	}
+
+	var deaths []PlayerDied
+	for index, player := range game.players {
+		if !player.Alive {
+			continue
+		}
+
+		for _, pos := range player.Occupied {
+			if game.gameMap.IsWall(pos) {
+				deaths = append(deaths, PlayerDied{
+					Player:   index,
+					Cause:    CauseWall,
+					Opponent: NoOpponent,
+					Position: pos,
+				})
+				break
+			}
+		}
+	}
+
+	return deaths
+}
+
+func (game *Game) eatCandies(playerIndex int) {
+	player := &game.players[playerIndex]
+
+	for i := len(game.candies) - 1; i >= 0; i-- {
+		candy := game.candies[i]
+		if candyIndex := player.Head().getCollision([]Position{candy.Position}); candyIndex != nil {
+			game.events = append(game.events, CandyEaten{Player: playerIndex, Candy: candy.CandyTpe, Position: candy.Position})
+			switch candy.CandyTpe {
+			case CandyGrow:
+				player.eat(game.rules.GrowSize)
+				for _, index := range game.teammates(playerIndex) {
+					if index != playerIndex {
+						game.players[index].Points += 1
+					}
+				}
+				game.candies[i] = NewCandyGrow(game.candyPosition())
+			default:
+				if perk, ok := candy.CandyTpe.Perk(); ok && perk.IsTimed() {
+					player.Perks.activate(perk, game.rules.PerkTicks)
+				} else if ok {
+					player.Perks.add(perk, 1)
+				}
+				game.candies = append(game.candies[:i], game.candies[i+1:]...)
+				continue
+			}
+		}
+	}
+}
+
+func hasDied(deaths []PlayerDied, playerIndex int) bool {
+	for _, death := range deaths {
+		if death.Player == playerIndex {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package game
+
+// Event is something that happened during a game step.
+type Event interface {
+	isEvent()
+}
+
+type DeathCause int
+
+const (
+	CauseWall DeathCause = iota
+	CauseSelf
+	CauseOpponent
+	CauseHeadOn
+)
+
+func (dc DeathCause) String() string {
+	switch dc {
+	case CauseWall:
+		return "wall"
+	case CauseSelf:
+		return "self"
+	case CauseOpponent:
+		return "opponent"
+	case CauseHeadOn:
+		return "head-on"
+	}
+
+	return "Unkown"
+}
+
+// NoOpponent is the opponent of deaths not caused by another snake.
+const NoOpponent = -1
+
+type PlayerDied struct {
+	Player   int
+	Cause    DeathCause
+	Opponent int
+	Position Position
+}
+
+func (PlayerDied) isEvent() {}
+
+// CandyEaten is sent for grow and perk candies.
+type CandyEaten struct {
+	Player   int
+	Candy    CandyTpe
+	Position Position
+}
+
+func (CandyEaten) isEvent() {}
+
+// PerkUsed is sent when a perk with usages was used up by one.
+type PerkUsed struct {
+	Player   int
+	Perk     PerkType
+	Position Position
+}
+
+func (PerkUsed) isEvent() {}
+
+type LevelChanged struct {
+	Level uint16
+}
+
+func (LevelChanged) isEvent() {}
+
+// DrainEvents returns the events since the last call and forgets them.
+func (game *Game) DrainEvents() []Event {
+	events := game.events
+	game.events = nil
+
+	return events
+}
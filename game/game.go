@@ -4,37 +4,70 @@ import (
 	"math/rand/v2"
 )
 
-const growsSize = 5
-const MapSwitch = 10
-
 type Game struct {
-	level   uint16
-	gameMap *Map
-	state   GameState
-	players []Snake
-	candies []Candy
+	seed      uint64
+	rng       *rand.Rand
+	rules     Rules
+	level     uint16
+	customMap *Map
+	levels    []*Map
+	gameMap   *Map
+	state     GameState
+	players   []Snake
+	candies   []Candy
+	events    []Event
+	ticks     uint32
+	shrink    uint16
+}
+
+// NewGame creates a game whose random values are all drawn from the given seed,
+// so the same seed and the same inputs always lead to the same match. It
+// fails if the levels do not have the given dimensions.
+func NewGame(player, width, height int, rules Rules, seed uint64) (*Game, error) {
+	levels, err := LoadLevels(uint16(width), uint16(height))
+	if err != nil {
+		return nil, err
+	}
+
+	return newGame(player, levels, nil, rules, seed), nil
+}
+
+// NewCustomGame creates a game that is played on the given map in every level.
+func NewCustomGame(player int, gameMap *Map, rules Rules, seed uint64) *Game {
+	return newGame(player, nil, gameMap, rules, seed)
 }
 
-func NewGame(player, width, height int) *Game {
+func newGame(player int, levels []*Map, customMap *Map, rules Rules, seed uint64) *Game {
 	game := &Game{
-		level:   1,
-		gameMap: NewMap(1, uint16(width), uint16(height)),
+		seed:      seed,
+		rng:       rand.New(rand.NewPCG(seed, seed)),
+		rules:     rules,
+		level:     1,
+		customMap: customMap,
+		levels:    levels,
 	}
+	game.gameMap = game.loadMap()
 
-	var players []Snake
-	for i := 1; i <= player; i++ {
-		startPos := game.randomPosition()
-		players = append(players, NewSnake(startPos.X, startPos.Y, game.gameMap.FarestWall(startPos)))
+	for i := 0; i < player; i++ {
+		startPos := game.spawnPosition()
+		game.players = append(game.players, NewSnake(startPos.X, startPos.Y, game.gameMap.FarestWall(startPos), rules.TeamOf(i), game.rules))
 	}
 
-	game.players = players
 	game.candies = []Candy{
-		NewCandyGrow(game.randomPosition()),
+		NewCandyGrow(game.candyPosition()),
 	}
 
 	return game
 }
 
+func (game *Game) Seed() uint64 {
+	return game.seed
+}
+
+func (game *Game) Rules() Rules {
+	return game.rules
+}
+
 func (game *Game) Level() uint16 {
 	return game.level
 }
@@ -43,6 +76,27 @@ func (game *Game) State() GameState {
 	return game.state
 }
 
+func (game *Game) Map() *Map {
+	return game.gameMap
+}
+
+// CustomMap returns the map the game was created with, or nil if the game
+// is played on the embedded levels.
+func (game *Game) CustomMap() *Map {
+	return game.customMap
+}
+
+// Shrink returns the number of wall rings the battle royale map has
+// shrunk by in the current round.
+func (game *Game) Shrink() uint16 {
+	return game.shrink
+}
+
+// Ticks returns the number of ticks played in the current round.
+func (game *Game) Ticks() uint32 {
+	return game.ticks
+}
+
 func (game *Game) Height() uint16 {
 	return game.gameMap.Height()
 }
@@ -60,24 +114,36 @@ func (game *Game) TooglePaused() {
 }
 
 func (game *Game) Reset() {
+	game.ticks = 0
+	game.shrink = 0
+
 	if game.state == RoundFinished {
 		game.state = Ongoing
-		game.candies = []Candy{NewCandyGrow(game.randomPosition())}
-		game.gameMap = NewMap(game.level, uint16(game.Width()), uint16(game.Height()))
+		game.gameMap = game.loadMap()
+		game.candies = []Candy{NewCandyGrow(game.candyPosition())}
 
 		for i := range game.players {
-			startPos := game.randomPosition()
+			startPos := game.spawnPosition()
 			game.players[i].reset(startPos.X, startPos.Y, game.gameMap.FarestWall(startPos))
+
+			// Without lives a battle royale snake sits out the rest of the
+			// match
+			if game.rules.IsBattleRoyale() && game.players[i].Lives == 0 {
+				game.players[i].Alive = false
+			}
 		}
 	} else {
+		if game.level != 1 {
+			game.events = append(game.events, LevelChanged{Level: 1})
+		}
 		game.level = 1
 		game.state = Paused
-		game.gameMap = NewMap(game.level, uint16(game.Width()), uint16(game.Height()))
-		game.candies = []Candy{NewCandyGrow(game.randomPosition())}
+		game.gameMap = game.loadMap()
+		game.candies = []Candy{NewCandyGrow(game.candyPosition())}
 
 		for i := range game.players {
-			startPos := game.randomPosition()
-			game.players[i] = NewSnake(startPos.X, startPos.Y, game.gameMap.FarestWall(startPos))
+			startPos := game.spawnPosition()
+			game.players[i] = NewSnake(startPos.X, startPos.Y, game.gameMap.FarestWall(startPos), game.rules.TeamOf(i), game.rules)
 		}
 	}
 }
@@ -94,13 +160,13 @@ func (game *Game) Field(playerIndex int, position Position) Field {
 	}
 
 	for _, snakePos := range game.players[playerIndex].Occupied {
-		if position.Y == snakePos.Y && position.X == snakePos.X {
+		if game.players[playerIndex].Alive && position.Y == snakePos.Y && position.X == snakePos.X {
 			return FieldSnakePlayer
 		}
 	}
 
 	for index, player := range game.players {
-		if index == playerIndex {
+		if index == playerIndex || !player.Alive {
 			continue
 		}
 
@@ -119,96 +185,95 @@ func (game *Game) Tick() {
 		return
 	}
 
+	moved := make([]int, 0, len(game.players))
+	prev := make(map[int]Snake, len(game.players))
+	game.ticks++
+
 	for index := range game.players {
 		player := &game.players[index]
+		if !player.Alive {
+			continue
+		}
 
+		prev[index] = player.snapshot()
 		player.move()
-		player.walkWalls(game)
+		game.walkWalls(index)
+		moved = append(moved, index)
 	}
 
-	// Spawn WalkWall
-	if rand.IntN(250) == 0 {
-		game.candies = append(game.candies, Candy{
-			CandyTpe: CandyWalkWall,
-			Position: game.randomPosition(),
-		})
+	if game.rules.PerksEnabled() {
+		for _, pc := range perkCandies {
+			if game.rng.Uint64N(uint64(game.rules.PerkSpawnOdds)*uint64(pc.rarity)) == 0 {
+				game.candies = append(game.candies, Candy{
+					CandyTpe: pc.candy,
+					Position: game.candyPosition(),
+				})
+			}
+		}
 	}
 
-	// Spawn Dash
-	if rand.IntN(250) == 0 {
-		game.candies = append(game.candies, Candy{
-			CandyTpe: CandyDash,
-			Position: game.randomPosition(),
-		})
+	deaths, _ := game.useShields(game.collisions(moved, prev), prev)
+	game.kill(deaths)
+
+	for _, index := range moved {
+		if !hasDied(deaths, index) && game.players[index].Perks.Active(PerkTypeMagnet) {
+			game.pullCandies(index)
+		}
+		game.players[index].Perks.tick()
 	}
 
 	candyCount := 0
-	for index := range game.players {
-		game.handelCollision(index)
-		candyCount += (len(game.players[index].Occupied) + int(game.players[index].grows)) / growsSize
+	for _, index := range moved {
+		if !hasDied(deaths, index) {
+			game.eatCandies(index)
+		}
+		candyCount += (len(game.players[index].Occupied) + int(game.players[index].grows)) / int(game.rules.GrowSize)
 	}
 
-	if candyCount >= MapSwitch {
-		game.level += 1
-
-		if game.level > 10 {
-			game.state = GameFinished
-		} else {
-			game.state = RoundFinished
+	if game.rules.IsBattleRoyale() {
+		if game.state == Ongoing && game.rules.ShrinkTicks > 0 && game.ticks%game.rules.ShrinkTicks == 0 {
+			game.kill(game.shrinkMap())
 		}
+
+		return
 	}
-}
 
-func (game *Game) handelCollision(playerIndex int) {
-	player := &game.players[playerIndex]
+	if candyCount >= int(game.rules.MapSwitch) && game.state != GameFinished {
+		game.level += 1
 
-	handleCollision := func() {
-		player.Lives -= 1
-		if player.Lives == 0 {
+		if game.level > LevelCount() {
 			game.state = GameFinished
 		} else {
 			game.state = RoundFinished
+			game.events = append(game.events, LevelChanged{Level: game.level})
 		}
 	}
+}
 
-	if game.gameMap.IsWall(player.Head()) {
-		handleCollision()
-		return
-	}
-	if collision := player.Head().getCollision(player.body()); collision != nil {
-		handleCollision()
-		return
+// teammates returns the indexes of all players in the team of the given
+// player, including the player. Without teams that is the player alone.
+func (game *Game) teammates(playerIndex int) []int {
+	team := game.players[playerIndex].Team
+	if team == 0 {
+		return []int{playerIndex}
 	}
 
-	// Snake Crushed to other Snake
-	for collisionIndex, collisionPlayer := range game.players {
-		if collisionIndex == playerIndex {
-			continue
-		}
-		if collision := player.Head().getCollision(collisionPlayer.Occupied); collision != nil {
-			handleCollision()
-			return
+	var indexes []int
+	for index, player := range game.players {
+		if player.Team == team {
+			indexes = append(indexes, index)
 		}
 	}
 
-	for i := len(game.candies) - 1; i >= 0; i-- {
-		candy := game.candies[i]
-		if candyIndex := player.Head().getCollision([]Position{candy.Position}); candyIndex != nil {
-			switch candy.CandyTpe {
-			case CandyGrow:
-				player.eat(growsSize)
-				game.candies[i] = NewCandyGrow(game.randomPosition())
-			case CandyDash:
-				player.Perks.add(PerkTypeDash, 1)
-				game.candies = append(game.candies[:i], game.candies[i+1:]...)
-				continue
-			case CandyWalkWall:
-				player.Perks.add(PerkTypeWalkWall, 1)
-				game.candies = append(game.candies[:i], game.candies[i+1:]...)
-				continue
-			}
-		}
-	}
+	return indexes
+}
+
+// isFriendly reports if two different players are in the same team and
+// can not hurt each other.
+func (game *Game) isFriendly(index, other int) bool {
+	team := game.players[index].Team
+
+	return !game.rules.FriendlyFire && team != 0 && team == game.players[other].Team
 }
 
 func (game *Game) ChangeDirection(playerIndex int, direction Direction) {
@@ -222,19 +287,58 @@ func (game *Game) Dash(playerIndex int) {
 		return
 	}
 
-	if playerIndex >= 0 && playerIndex < len(game.players) {
+	if playerIndex >= 0 && playerIndex < len(game.players) && game.players[playerIndex].Alive {
 		if ok := game.players[playerIndex].Perks.use(PerkTypeDash); !ok {
 			return
 		}
+		game.usedPerk(playerIndex, PerkTypeDash)
+
+		for i := uint8(0); i < game.rules.DashLength && game.state == Ongoing; i++ {
+			prev := map[int]Snake{playerIndex: game.players[playerIndex].snapshot()}
 
-		for i := 0; i < 5; i++ {
 			game.players[playerIndex].move()
-			game.players[playerIndex].walkWalls(game)
-			game.handelCollision(playerIndex)
+			game.walkWalls(playerIndex)
+
+			deaths, shielded := game.useShields(game.collisions([]int{playerIndex}, prev), prev)
+			game.kill(deaths)
+			if len(shielded) > 0 {
+				// The shield stops the dash in front of the obstacle
+				return
+			}
+			if !hasDied(deaths, playerIndex) {
+				game.eatCandies(playerIndex)
+			}
+		}
+	}
+}
+
+func (game *Game) Reverse(playerIndex int) {
+	if game.state != Ongoing {
+		return
+	}
+
+	if playerIndex >= 0 && playerIndex < len(game.players) && game.players[playerIndex].Alive {
+		if ok := game.players[playerIndex].Perks.use(PerkTypeReverse); ok {
+			game.players[playerIndex].reverse()
+			game.usedPerk(playerIndex, PerkTypeReverse)
 		}
 	}
 }
 
+func (game *Game) walkWalls(playerIndex int) {
+	if game.players[playerIndex].walkWalls(game.Width(), game.Height()) {
+		game.usedPerk(playerIndex, PerkTypeWalkWall)
+	}
+}
+
+func (game *Game) usedPerk(playerIndex int, perk PerkType) {
+	game.events = append(game.events, PerkUsed{
+		Player:   playerIndex,
+		Perk:     perk,
+		Position: game.players[playerIndex].Head(),
+	})
+}
+
 func (game *Game) Players() []Snake {
 	return game.players
 }
@@ -243,10 +347,64 @@ func (game *Game) Candies() []Candy {
 	return game.candies
 }
 
+func (game *Game) loadMap() *Map {
+	if game.customMap != nil {
+		return game.customMap.Clone()
+	}
+
+	return game.levels[game.level-1].Clone()
+}
+
+// spawnPosition returns a free spawn point of the map, or a random position
+// if the map has none left.
+func (game *Game) spawnPosition() Position {
+	return game.randomPositionOf(game.gameMap.Spawns())
+}
+
+// candyPosition returns a free field of the maps candy zones, or a random
+// position if the map has none left.
+func (game *Game) candyPosition() Position {
+	return game.randomPositionOf(game.gameMap.CandyZones())
+}
+
+func (game *Game) randomPositionOf(positions []Position) Position {
+	free := make([]Position, 0, len(positions))
+	for _, pos := range positions {
+		if !game.isOccupied(pos) {
+			free = append(free, pos)
+		}
+	}
+
+	if len(free) == 0 {
+		return game.randomPosition()
+	}
+
+	return free[game.rng.IntN(len(free))]
+}
+
+func (game *Game) isOccupied(pos Position) bool {
+	for _, player := range game.players {
+		if !player.Alive {
+			continue
+		}
+		if collision := pos.getCollision(player.Occupied); collision != nil {
+			return true
+		}
+	}
+
+	for _, candy := range game.candies {
+		if candy.Position == pos {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (game *Game) randomPosition() Position {
 	pos := Position{
-		Y: uint16(rand.N(game.gameMap.Height()-2) + 1),
-		X: uint16(rand.N(game.gameMap.Width()-2) + 1),
+		Y: uint16(game.rng.UintN(uint(game.gameMap.Height()-2)) + 1),
+		X: uint16(game.rng.UintN(uint(game.gameMap.Width()-2)) + 1),
 	}
 
 	if game.gameMap.IsWall(pos) {
@@ -254,7 +412,7 @@ func (game *Game) randomPosition() Position {
 	}
 
 	for _, player := range game.players {
-		if collision := pos.getCollision(player.Occupied); collision != nil {
+		if collision := pos.getCollision(player.Occupied); collision != nil && player.Alive {
 			return game.randomPosition()
 		}
 	}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+var testKeys = []Input{InputNone, InputNone, InputNone, InputNorth, InputSouth, InputWest, InputEast, InputDash, InputReverse}
+
+// playRandom steps the game with random inputs drawn from the given seed and
+// starts it again whenever it is paused or finished.
+func playRandom(g *Game, seed uint64, steps int) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	for i := 0; i < steps; i++ {
+		inputs := make([]Input, len(g.Players()))
+		for j := range inputs {
+			inputs[j] = testKeys[rng.IntN(len(testKeys))]
+		}
+		if g.State() != Ongoing {
+			inputs[0] = InputConfirm
+		}
+
+		g.Step(inputs)
+		g.DrainEvents()
+	}
+}
+
+func TestStepIsDeterministic(t *testing.T) {
+	rules := DefaultRules()
+
+	a, err := NewGame(3, 50, 50, rules, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewGame(3, 50, 50, rules, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	playRandom(a, 7, 2000)
+	playRandom(b, 7, 2000)
+
+	if a.Ticks() == 0 {
+		t.Fatal("the game never ran")
+	}
+	if a.Level() != b.Level() || a.State() != b.State() || a.Ticks() != b.Ticks() {
+		t.Fatalf("level, state or ticks differ: %d/%d/%d and %d/%d/%d", a.Level(), a.State(), a.Ticks(), b.Level(), b.State(), b.Ticks())
+	}
+	if !reflect.DeepEqual(a.Players(), b.Players()) {
+		t.Fatal("players differ")
+	}
+	if !reflect.DeepEqual(a.Candies(), b.Candies()) {
+		t.Fatal("candies differ")
+	}
+}
+
+func TestHugePerkSpawnOdds(t *testing.T) {
+	rules := DefaultRules()
+	// Times the rarity of 2 it no longer fits into 32 bits
+	rules.PerkSpawnOdds = 1 << 31
+
+	g, err := NewGame(2, 50, 50, rules, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	playRandom(g, 7, 100)
+}
+
+func TestBattleRoyaleGoesOnWithoutSnakesOutOfLives(t *testing.T) {
+	rules := DefaultRules()
+	rules.Mode = ModeBattleRoyale
+	rules.Lives = 2
+
+	g, err := NewGame(3, 50, 50, rules, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirm := func() {
+		g.Step([]Input{InputConfirm, InputNone, InputNone})
+		if g.State() != Ongoing {
+			t.Fatalf("state is %v after confirming", g.State())
+		}
+	}
+	kill := func(player int, want GameState) {
+		g.kill([]PlayerDied{{Player: player, Opponent: NoOpponent}})
+		if g.State() != want {
+			t.Fatalf("state is %v after player %d died, want %v", g.State(), player+1, want)
+		}
+	}
+
+	confirm()
+	kill(0, Ongoing)
+	kill(1, RoundFinished)
+	confirm()
+
+	// The first snake loses its last life, the other two play on
+	kill(0, Ongoing)
+	kill(2, RoundFinished)
+	confirm()
+
+	if g.Players()[0].Alive {
+		t.Fatal("the snake without lives is back in the next round")
+	}
+	if !g.Players()[1].Alive || !g.Players()[2].Alive {
+		t.Fatal("the snakes with lives are not back in the next round")
+	}
+
+	kill(1, GameFinished)
+}
@@ -0,0 +1,50 @@
+package game
+
+type Input rune
+
+const (
+	InputNone    Input = 0
+	InputNorth   Input = 'w'
+	InputSouth   Input = 's'
+	InputWest    Input = 'a'
+	InputEast    Input = 'd'
+	InputDash    Input = ' '
+	InputReverse Input = 'r'
+	InputConfirm Input = '↵'
+)
+
+// Step applies one input per player, in player order, and advances the game
+// by one tick. It is the only way the server drives the simulation, so a
+// match can be rebuilt from its seed and the inputs of every step.
+func (game *Game) Step(inputs []Input) {
+	for playerIndex, input := range inputs {
+		if game.state != Ongoing {
+			if input == InputConfirm {
+				if game.state == Paused {
+					game.TooglePaused()
+				} else {
+					game.Reset()
+				}
+				return
+			}
+			continue
+		}
+
+		switch input {
+		case InputNorth:
+			game.ChangeDirection(playerIndex, North)
+		case InputSouth:
+			game.ChangeDirection(playerIndex, South)
+		case InputWest:
+			game.ChangeDirection(playerIndex, West)
+		case InputEast:
+			game.ChangeDirection(playerIndex, East)
+		case InputDash:
+			game.Dash(playerIndex)
+		case InputReverse:
+			game.Reverse(playerIndex)
+		}
+	}
+
+	game.Tick()
+}
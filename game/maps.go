@@ -1,101 +1,163 @@
 package game
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
 	"log"
+	"maps"
 	"slices"
+	"strings"
+
+	mapAsset "github.com/apfelfrisch/gosnake/game/assets/maps"
+)
+
+// Maps are plain text grids, one line per row. 'X' is a wall, '.' an empty
+// field, 'S' a spawn point and 'C' a field where candies may spawn. If a map
+// has no spawn points or candy fields, any empty field is used instead.
+const (
+	MapWall      = FieldWall
+	MapEmpty     = '.'
+	MapSpawn     = 'S'
+	MapCandyZone = 'C'
 )
 
+var levelCount = countLevels()
+
 type Map struct {
-	width  uint16
-	height uint16
-	walls  map[uint16]map[uint16]bool
+	width      uint16
+	height     uint16
+	walls      map[uint16]map[uint16]bool
+	spawns     []Position
+	candyZones []Position
 }
 
-func NewMap(level, gameWidth, gameHeight uint16) *Map {
-	walls := outerWalls(gameWidth, gameHeight)
-
-	switch level {
-	case 2:
-		wallLen := gameWidth / 2
-		for width := wallLen / 2; width <= gameWidth-wallLen/2; width++ {
-			walls[gameHeight/2-1][uint16(width)] = true
-		}
-	case 3:
-		for height := gameHeight / 4; height <= gameHeight-gameHeight/4; height++ {
-			walls[uint16(height)][10] = true
-			walls[uint16(height)][40] = true
-		}
-	case 4:
-		wallLen := gameWidth / 2
-		for width := uint16(1); width <= wallLen; width++ {
-			walls[gameHeight/4][gameWidth-width] = true
-			walls[gameHeight-gameHeight/4][width] = true
-		}
+// NewEmptyMap returns a map with nothing but the outer walls.
+func NewEmptyMap(width, height uint16) *Map {
+	m := &Map{
+		width:  width,
+		height: height,
+		walls:  make(map[uint16]map[uint16]bool),
+	}
 
-		for height := uint16(1); height <= wallLen; height++ {
-			walls[gameHeight-height][gameWidth-gameWidth/4] = true
-			walls[height][gameWidth/4] = true
-		}
-	case 5:
-		wallLen := gameWidth / 2
-		for width := uint16(1); width <= wallLen-2; width++ {
-			walls[gameHeight/4][gameWidth/4+width+1] = true
-			walls[gameHeight-gameHeight/4][gameWidth/4+width+1] = true
-		}
-		for height := uint16(1); height <= wallLen-2; height++ {
-			walls[gameHeight/4+height+1][gameWidth/4] = true
-			walls[gameHeight/4+height+1][gameHeight-gameHeight/4] = true
-		}
-	case 6:
-		wallLen := gameHeight/2 - gameHeight/8
-		for height := uint16(1); height <= wallLen; height++ {
-			for i := uint16(1); i < 7; i++ {
-				walls[height][gameWidth/7*i+1] = true
-			}
-			for i := uint16(1); i < 7; i++ {
-				walls[gameHeight-height][gameWidth/7*i+1] = true
+	for y := uint16(1); y <= height; y++ {
+		m.walls[y] = make(map[uint16]bool)
+		for x := uint16(1); x <= width; x++ {
+			if m.isBorder(Position{Y: y, X: x}) {
+				m.walls[y][x] = true
 			}
 		}
-	case 7:
-		for height := uint16(1); height <= gameHeight; height++ {
-			if height%2 == 0 {
-				walls[height][gameWidth/2] = true
-			}
+	}
+
+	return m
+}
+
+// LevelCount returns the number of embedded levels.
+func LevelCount() uint16 {
+	return levelCount
+}
+
+// LoadMap loads an embedded level. It fails if the level does not have the
+// given dimensions.
+func LoadMap(level, gameWidth, gameHeight uint16) (*Map, error) {
+	name := levelFile(level)
+
+	f, err := mapAsset.Files.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("map %s: %w", name, err)
+	}
+	defer f.Close()
+
+	m, err := ParseMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("map %s: %w", name, err)
+	}
+
+	if m.width != gameWidth || m.height != gameHeight {
+		return nil, fmt.Errorf("map %s: wrong dimensions %dx%d, expected %dx%d", name, m.width, m.height, gameWidth, gameHeight)
+	}
+
+	return m, nil
+}
+
+// LoadLevels loads all embedded levels, so a game fails right away if one of
+// them does not fit.
+func LoadLevels(gameWidth, gameHeight uint16) ([]*Map, error) {
+	levels := make([]*Map, 0, levelCount)
+	for level := uint16(1); level <= levelCount; level++ {
+		m, err := LoadMap(level, gameWidth, gameHeight)
+		if err != nil {
+			return nil, err
 		}
-	case 8:
-		wallLen := gameHeight - gameHeight/6
-		for i := uint16(1); i < 7; i++ {
-			for height := uint16(1); height <= wallLen; height++ {
-				if i%2 == 0 {
-					walls[height][gameWidth/7*i+1] = true
-				} else {
-					walls[gameHeight-height][gameWidth/7*i+1] = true
-				}
-			}
+		levels = append(levels, m)
+	}
+
+	return levels, nil
+}
+
+func ParseMap(r io.Reader) (*Map, error) {
+	var rows []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		rows = append(rows, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+
+	if len(rows) < 3 {
+		return nil, fmt.Errorf("wrong dimensions: map needs at least 3 lines, got %d", len(rows))
+	}
+
+	m := &Map{
+		width:  uint16(len(rows[0])),
+		height: uint16(len(rows)),
+		walls:  make(map[uint16]map[uint16]bool),
+	}
+
+	for i, row := range rows {
+		y := uint16(i + 1)
+		m.walls[y] = make(map[uint16]bool)
+
+		if len(row) != int(m.width) {
+			return nil, fmt.Errorf("wrong dimensions: line %d has %d columns, expected %d", y, len(row), m.width)
 		}
-	case 9:
-		width := uint16(5)
-		height := uint16(15)
-		for {
-			if width+5 > gameWidth || height+5 > gameHeight {
-				break
+
+		for j, field := range row {
+			x := uint16(j + 1)
+			pos := Position{Y: y, X: x}
+
+			switch field {
+			case MapWall:
+				m.walls[y][x] = true
+				continue
+			case MapEmpty:
+			case MapSpawn:
+				m.spawns = append(m.spawns, pos)
+			case MapCandyZone:
+				m.candyZones = append(m.candyZones, pos)
+			default:
+				return nil, fmt.Errorf("line %d, column %d: unknown field %q", y, x, field)
 			}
-			walls[height][width] = true
-			walls[height-10][width+10] = true
-			width += 1
-			height += 1
-		}
-	case 10:
-		for i := uint16(1); i < 7; i++ {
-			for height := uint16(1); height <= gameHeight; height++ {
-				if (height+i)%2 == 0 {
-					walls[height][gameWidth/7*i+1] = true
-				}
+
+			if m.isBorder(pos) {
+				return nil, fmt.Errorf("line %d, column %d: the map border must be a wall", y, x)
 			}
 		}
 	}
 
-	return &Map{gameWidth, gameHeight, walls}
+	if pos := m.unreachable(); pos != nil {
+		return nil, fmt.Errorf("line %d, column %d: field is not reachable from the rest of the map", pos.Y, pos.X)
+	}
+
+	return m, nil
 }
 
 func (self *Map) Width() uint16 {
@@ -106,12 +168,157 @@ func (self *Map) Height() uint16 {
 	return self.height
 }
 
+func (self *Map) Spawns() []Position {
+	return self.spawns
+}
+
+func (self *Map) CandyZones() []Position {
+	return self.candyZones
+}
+
+// SetField changes a field inside the outer walls to one of the map fields.
+// A field is either a wall, a spawn point, a candy zone or empty.
+func (self *Map) SetField(pos Position, field rune) error {
+	if pos.X < 1 || pos.Y < 1 || pos.X > self.width || pos.Y > self.height {
+		return fmt.Errorf("position %v is outside the map", pos)
+	}
+	if self.isBorder(pos) {
+		return fmt.Errorf("position %v is part of the map border", pos)
+	}
+
+	delete(self.walls[pos.Y], pos.X)
+	self.spawns = slices.DeleteFunc(self.spawns, func(p Position) bool { return p == pos })
+	self.candyZones = slices.DeleteFunc(self.candyZones, func(p Position) bool { return p == pos })
+
+	switch field {
+	case MapWall:
+		self.walls[pos.Y][pos.X] = true
+	case MapSpawn:
+		self.spawns = append(self.spawns, pos)
+	case MapCandyZone:
+		self.candyZones = append(self.candyZones, pos)
+	case MapEmpty:
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+
+	return nil
+}
+
+// MapField returns the map field at the given position, as used in map files.
+func (self *Map) MapField(pos Position) rune {
+	switch {
+	case self.IsWall(pos):
+		return MapWall
+	case slices.Contains(self.spawns, pos):
+		return MapSpawn
+	case slices.Contains(self.candyZones, pos):
+		return MapCandyZone
+	default:
+		return MapEmpty
+	}
+}
+
+// MarshalText encodes the map in the text format read by ParseMap.
+func (self *Map) MarshalText() ([]byte, error) {
+	var b bytes.Buffer
+	for y := uint16(1); y <= self.height; y++ {
+		for x := uint16(1); x <= self.width; x++ {
+			b.WriteRune(self.MapField(Position{Y: y, X: x}))
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes(), nil
+}
+
+// Validate checks that a map is playable, it fails for the same
+// reasons as ParseMap.
+func (self *Map) Validate() error {
+	text, _ := self.MarshalText()
+	_, err := ParseMap(bytes.NewReader(text))
+
+	return err
+}
+
+func (self *Map) Clone() *Map {
+	clone := &Map{
+		width:      self.width,
+		height:     self.height,
+		walls:      make(map[uint16]map[uint16]bool, len(self.walls)),
+		spawns:     slices.Clone(self.spawns),
+		candyZones: slices.Clone(self.candyZones),
+	}
+
+	for y, row := range self.walls {
+		clone.walls[y] = maps.Clone(row)
+	}
+
+	return clone
+}
+
 func (self *Map) IsWall(pos Position) bool {
 	_, exists := self.walls[pos.Y][pos.X]
 
 	return exists
 }
 
+// Shrink turns every field within the given number of rings inside the
+// border into a wall.
+func (self *Map) Shrink(rings uint16) {
+	for y := uint16(1); y <= self.height; y++ {
+		for x := uint16(1); x <= self.width; x++ {
+			pos := Position{Y: y, X: x}
+			if self.borderDistance(pos) > rings || self.IsWall(pos) {
+				continue
+			}
+
+			self.walls[y][x] = true
+			self.spawns = slices.DeleteFunc(self.spawns, func(p Position) bool { return p == pos })
+			self.candyZones = slices.DeleteFunc(self.candyZones, func(p Position) bool { return p == pos })
+		}
+	}
+}
+
+// MaxShrink returns the number of rings the map can shrink, while keeping
+// an open area of at least minSize fields in both dimensions.
+func (self *Map) MaxShrink(minSize uint16) uint16 {
+	inner := min(self.width, self.height) - 2
+	if inner <= minSize {
+		return 0
+	}
+
+	return (inner - minSize) / 2
+}
+
+func (self *Map) borderDistance(pos Position) uint16 {
+	return min(pos.X-1, pos.Y-1, self.width-pos.X, self.height-pos.Y)
+}
+
+func (self *Map) World() []FieldPos {
+	fieldPos := make([]FieldPos, 0, self.Width()*self.Height())
+
+	var x, y uint16
+	for y = 1; y <= self.Height(); y++ {
+		for x = 1; x <= self.Width(); x++ {
+			pos := Position{Y: y, X: x}
+			if self.IsWall(pos) {
+				fieldPos = append(fieldPos, FieldPos{
+					Field:    FieldWall,
+					Position: pos,
+				})
+			} else {
+				fieldPos = append(fieldPos, FieldPos{
+					Field:    FieldEmpty,
+					Position: pos,
+				})
+			}
+		}
+	}
+
+	return fieldPos
+}
+
 func (self *Map) FarestWall(pos Position) Direction {
 	directions := []Direction{North, East, West, South}
 	positions := map[Direction]Position{
@@ -149,19 +356,60 @@ func (self *Map) FarestWall(pos Position) Direction {
 	return directions[0]
 }
 
-func outerWalls(gameWidth, gameHeight uint16) map[uint16]map[uint16]bool {
-	walls := make(map[uint16]map[uint16]bool)
-	for height := uint16(1); height <= gameHeight; height++ {
-		walls[height] = make(map[uint16]bool)
-		for width := uint16(1); width <= gameWidth; width++ {
-			// Outer Wall
-			if height == 1 || height == gameHeight {
-				walls[height][width] = true
+func (self *Map) isBorder(pos Position) bool {
+	return pos.Y == 1 || pos.Y == self.height || pos.X == 1 || pos.X == self.width
+}
+
+// unreachable returns the first empty field a snake can not reach from the
+// first empty field of the map, or nil if every field is reachable.
+func (self *Map) unreachable() *Position {
+	var open []Position
+	for y := uint16(1); y <= self.height; y++ {
+		for x := uint16(1); x <= self.width; x++ {
+			if pos := (Position{Y: y, X: x}); !self.IsWall(pos) {
+				open = append(open, pos)
 			}
-			if width == 1 || width == gameWidth {
-				walls[height][width] = true
+		}
+	}
+
+	if len(open) == 0 {
+		return nil
+	}
+
+	visited := map[Position]bool{open[0]: true}
+	queue := []Position{open[0]}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range []Direction{North, East, South, West} {
+			next := pos.Move(dir)
+			if !visited[next] && !self.IsWall(next) {
+				visited[next] = true
+				queue = append(queue, next)
 			}
 		}
 	}
-	return walls
+
+	for _, pos := range open {
+		if !visited[pos] {
+			return &pos
+		}
+	}
+
+	return nil
+}
+
+func levelFile(level uint16) string {
+	return fmt.Sprintf("level-%02d.txt", level)
+}
+
+func countLevels() uint16 {
+	var count uint16
+	for {
+		if _, err := fs.Stat(mapAsset.Files, levelFile(count+1)); err != nil {
+			return count
+		}
+		count++
+	}
 }
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/apfelfrisch/gosnake/game"
@@ -10,15 +13,46 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func Connect(ctx context.Context, serverAddr string, width, height int) (*GameClient, error) {
-	udp := NewUdpClient(serverAddr)
+// Connect joins the server over network, see NewTransport.
+func Connect(ctx context.Context, network, serverAddr string, width, height int, profile payload.Profile) (*GameClient, error) {
+	return connect(ctx, network, serverAddr, payload.NewHello(profile, ""), width, height)
+}
+
+// Spectate joins as a spectator, who gets every snake and takes no player
+// slot.
+func Spectate(ctx context.Context, network, serverAddr string, width, height int, profile payload.Profile) (*GameClient, error) {
+	hello := payload.NewHello(profile, "")
+	hello.Spectator = true
+
+	return connect(ctx, network, serverAddr, hello, width, height)
+}
+
+func connect(ctx context.Context, network, serverAddr string, hello payload.Hello, width, height int) (*GameClient, error) {
+	gameMap, err := game.LoadMap(1, uint16(width), uint16(height))
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := NewTransport(network, serverAddr, hello)
+	if err != nil {
+		return nil, err
+	}
 
 	for i := 0; i < 10; i++ {
-		if err := udp.Connect(ctx); err == nil {
+		err = transport.Connect(ctx)
+		if err == nil {
 			break
 		}
+
+		var rejected *RejectedError
+		if errors.As(err, &rejected) {
+			return nil, err
+		}
 		time.Sleep(time.Second / 10)
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	for {
 		select {
@@ -28,124 +62,238 @@ func Connect(ctx context.Context, serverAddr string, width, height int) (*GameCl
 			time.Sleep(time.Second / 10)
 		}
 
-		if len(udp.Read()) != 0 {
+		if len(transport.Read()) != 0 {
 			break
 		}
 	}
 
-	return &GameClient{
-		udp,
-		game.NewMap(1, uint16(width), uint16(height)),
-		&payload.Payload{},
-		NewEventBus(),
-	}, nil
+	gc := &GameClient{
+		ctx:       ctx,
+		transport: transport,
+		gameMap:   gameMap,
+		Payload:   &payload.Payload{},
+		EventBus:  NewEventBus(),
+		events:    make(chan Event, eventBuffer),
+	}
+	go gc.dispatchEvents()
+
+	return gc, nil
 }
 
+// eventBuffer is how many events wait for the listeners before
+// UpdatePayload blocks.
+const eventBuffer = 64
+
 type GameClient struct {
-	udp      *UdpClient
-	gameMap  *game.Map
-	Payload  *payload.Payload
-	EventBus *EventBus
+	ctx          context.Context
+	transport    Transport
+	reconnecting atomic.Bool
+	gameMap      *game.Map
+	Payload      *payload.Payload
+	EventBus     *EventBus
+	// snapshots are the decoded payloads by sequence, the baselines of
+	// the deltas the server sends.
+	snapshots  [payload.SnapshotHistory]payload.Payload
+	prediction prediction
+	latency    latencyMeter
+	// lastEvent is the sequence of the last dispatched event.
+	lastEvent uint32
+	events    chan Event
 }
 
 func (gc *GameClient) PressKey(char rune) {
-	gc.udp.Write(char)
+	sequence := gc.transport.Write(char, gc.Payload.Tick)
+	gc.prediction.press(sequence, char, time.Now())
+}
+
+// PredictedPlayer returns the own snake ahead of the last state, with the
+// key presses the server has not applied yet.
+func (gc *GameClient) PredictedPlayer() game.Snake {
+	return gc.prediction.predict(*gc.Payload, gc.gameMap.Width(), gc.gameMap.Height(), time.Now())
+}
+
+// ToggleReady tells the server in the lobby if the player is ready to start.
+func (gc *GameClient) ToggleReady() {
+	gc.transport.Write(READY, gc.Payload.Tick)
+}
+
+// Leave frees the player slot and closes the connection.
+func (gc *GameClient) Leave() {
+	gc.transport.Leave()
 }
 
 func (gc *GameClient) UpdatePayload() {
-	data := gc.udp.Read()
+	if gc.transport.IsSilent() && gc.reconnecting.CompareAndSwap(false, true) {
+		go gc.reconnect()
+	}
+
+	data := gc.transport.Read()
 
-	if string(data) == string(HANDSHAKE_RESP) {
+	if isHandshakeResponse(data) {
 		return
 	}
 
 	stalePayload := *gc.Payload
 
 	ppl := &payload.ProtoPayload{}
-	err := proto.Unmarshal(gc.udp.Read(), ppl)
+	err := proto.Unmarshal(data, ppl)
 	if err != nil {
 		log.Println(err)
-		log.Println(gc.udp.Read())
+		log.Println(data)
 		return
 	}
 
-	*gc.Payload = payload.PayloadFromProto(ppl)
+	// Late packets are older states, duplicates and resends of a tick were
+	// seen already. The lobby does not tick, so all of its states are new.
+	if ppl.Sequence <= stalePayload.Sequence || (stalePayload.Sequence != 0 && ppl.ServerTick <= stalePayload.ServerTick && ppl.Lobby == nil) {
+		return
+	}
 
-	if stalePayload.MapLevel != gc.Payload.MapLevel {
-		*gc.gameMap = *game.NewMap(gc.Payload.MapLevel, gc.gameMap.Width(), gc.gameMap.Height())
+	next, ok := gc.decode(ppl)
+	if !ok {
+		return
 	}
+	*gc.Payload = next
 
-	go func() {
-		if stalePayload.GameState != gc.Payload.GameState {
-			if gc.Payload.GameState == game.Ongoing {
-				gc.EventBus.Dispatch(GameHasStarted{})
-			} else {
-				gc.EventBus.Dispatch(GameHasEnded{})
-			}
-		}
+	arrived := gc.transport.ReceivedAt()
+	gc.prediction.observe(next, arrived)
+	gc.latency.observe(next, arrived)
 
-		if stalePayload.GameState != game.Ongoing {
-			return
-		}
+	if stalePayload.MapLevel != gc.Payload.MapLevel || stalePayload.Shrink != gc.Payload.Shrink || !bytes.Equal(stalePayload.Map, gc.Payload.Map) {
+		gc.loadMap()
+	}
 
-		if stalePayload.Player.Lives != gc.Payload.Player.Lives {
-			gc.EventBus.Dispatch(PlayerCrashed{})
+	if stalePayload.GameState != next.GameState {
+		if next.GameState == game.Ongoing {
+			gc.queue(GameHasStarted{})
 		} else {
-			for i, opp := range gc.Payload.Opponents {
-				if opp.Lives != stalePayload.Opponents[i].Lives {
-					gc.EventBus.Dispatch(PlayerCrashed{})
-					break
-				}
+			gc.queue(GameHasEnded{})
+		}
+	}
+
+	for _, event := range gc.unseenEvents(next.Events, stalePayload.Sequence == 0) {
+		gc.dispatch(event.Event)
+	}
+}
+
+// unseenEvents returns the events after the last one seen. The events in the
+// first state happened before the client joined, they are only marked as
+// seen.
+func (gc *GameClient) unseenEvents(events []payload.Event, first bool) []payload.Event {
+	var unseen []payload.Event
+	for _, event := range events {
+		if event.Sequence > gc.lastEvent {
+			gc.lastEvent = event.Sequence
+			if !first {
+				unseen = append(unseen, event)
 			}
 		}
+	}
 
-		if gc.Payload.GameState != game.Ongoing {
+	return unseen
+}
+
+// queue passes an event on to dispatchEvents.
+func (gc *GameClient) queue(event Event) {
+	select {
+	case gc.events <- event:
+	case <-gc.ctx.Done():
+	}
+}
+
+// dispatchEvents calls the listeners of the queued events one after the
+// other, so they get the events in order and never at the same time.
+func (gc *GameClient) dispatchEvents() {
+	for {
+		select {
+		case event := <-gc.events:
+			gc.EventBus.Dispatch(event)
+		case <-gc.ctx.Done():
 			return
 		}
+	}
+}
 
-		if stalePayload.Player.Points != gc.Payload.Player.Points {
-			gc.EventBus.Dispatch(PlayerHasEaten{})
-		} else {
-			for i, opp := range gc.Payload.Opponents {
-				if opp.Points != stalePayload.Opponents[i].Points {
-					gc.EventBus.Dispatch(PlayerHasEaten{})
-					break
-				}
-			}
+// dispatch passes an event of the server on to the listeners.
+func (gc *GameClient) dispatch(event game.Event) {
+	switch e := event.(type) {
+	case game.PlayerDied:
+		gc.queue(PlayerCrashed{Player: e.Player, Cause: e.Cause, Opponent: e.Opponent, Position: e.Position})
+	case game.CandyEaten:
+		gc.queue(PlayerHasEaten{Player: e.Player, Candy: e.Candy, Position: e.Position})
+	case game.PerkUsed:
+		switch e.Perk {
+		case game.PerkTypeDash:
+			gc.queue(PlayerDashed{Player: e.Player, Position: e.Position})
+		case game.PerkTypeWalkWall:
+			gc.queue(PlayerWalkedWall{Player: e.Player, Position: e.Position})
+		default:
+			gc.queue(PlayerUsedPerk{Player: e.Player, Perk: e.Perk, Position: e.Position})
 		}
+	case game.LevelChanged:
+		gc.queue(LevelHasChanged{Level: e.Level})
+	}
+}
 
-		if stalePayload.Player.Perks.Get(game.PerkTypeDash).Usages < gc.Payload.Player.Perks.Get(game.PerkTypeDash).Usages {
-			gc.EventBus.Dispatch(PlayerHasEaten{})
-		} else if stalePayload.Player.Perks.Get(game.PerkTypeDash).Usages > gc.Payload.Player.Perks.Get(game.PerkTypeDash).Usages {
-			gc.EventBus.Dispatch(PlayerDashed{})
-		} else {
-			for i, opp := range gc.Payload.Opponents {
-				if opp.Perks.Get(game.PerkTypeDash).Usages < stalePayload.Opponents[i].Perks.Get(game.PerkTypeDash).Usages {
-					gc.EventBus.Dispatch(PlayerHasEaten{})
-					break
-				} else if opp.Perks.Get(game.PerkTypeDash).Usages > stalePayload.Opponents[i].Perks.Get(game.PerkTypeDash).Usages {
-					gc.EventBus.Dispatch(PlayerDashed{})
-					break
-				}
-			}
+// decode resolves a delta against its baseline snapshot and acknowledges the
+// decoded snapshot. A delta against an unknown snapshot is dropped.
+func (gc *GameClient) decode(ppl *payload.ProtoPayload) (payload.Payload, bool) {
+	var next payload.Payload
+	if ppl.Baseline == 0 {
+		next = payload.PayloadFromProto(ppl)
+	} else {
+		base := gc.snapshots[ppl.Baseline%payload.SnapshotHistory]
+		if base.Sequence != ppl.Baseline {
+			return payload.Payload{}, false
 		}
+		next = payload.PayloadFromDeltaProto(ppl, base)
+	}
 
-		if stalePayload.Player.Perks.Get(game.PerkTypeWalkWall).Usages < gc.Payload.Player.Perks.Get(game.PerkTypeWalkWall).Usages {
-			gc.EventBus.Dispatch(PlayerHasEaten{})
-		} else if stalePayload.Player.Perks.Get(game.PerkTypeWalkWall).Usages > gc.Payload.Player.Perks.Get(game.PerkTypeWalkWall).Usages {
-			gc.EventBus.Dispatch(PlayerWalkedWall{})
-		} else {
-			for i, opp := range gc.Payload.Opponents {
-				if opp.Perks.Get(game.PerkTypeWalkWall).Usages < stalePayload.Opponents[i].Perks.Get(game.PerkTypeWalkWall).Usages {
-					gc.EventBus.Dispatch(PlayerHasEaten{})
-					break
-				} else if opp.Perks.Get(game.PerkTypeWalkWall).Usages > stalePayload.Opponents[i].Perks.Get(game.PerkTypeWalkWall).Usages {
-					gc.EventBus.Dispatch(PlayerWalkedWall{})
-					break
-				}
-			}
+	gc.snapshots[next.Sequence%payload.SnapshotHistory] = next
+	gc.transport.Ack(next.Sequence)
+	gc.transport.AckInputs(next.InputAck)
+
+	return next, true
+}
+
+// reconnect connects again with the session token, so the server hands the
+// player slot back to this client.
+func (gc *GameClient) reconnect() {
+	defer gc.reconnecting.Store(false)
+
+	log.Println("Lost connection to server, reconnecting")
+	if err := gc.transport.Reconnect(gc.ctx); err != nil {
+		log.Println("Could not reconnect:", err)
+		return
+	}
+	log.Println("Reconnected to server")
+}
+
+func (gc *GameClient) loadMap() {
+	if len(gc.Payload.Map) == 0 {
+		levelMap, err := game.LoadMap(gc.Payload.MapLevel, gc.gameMap.Width(), gc.gameMap.Height())
+		if err != nil {
+			log.Println("Could not load map:", err)
+			return
+		}
+
+		*gc.gameMap = *levelMap
+	} else {
+		customMap, err := game.ParseMap(bytes.NewReader(gc.Payload.Map))
+		if err != nil {
+			log.Println("Could not load map from server:", err)
+			return
 		}
-	}()
+
+		*gc.gameMap = *customMap
+	}
+
+	gc.gameMap.Shrink(gc.Payload.Shrink)
+}
+
+// Latency returns the measured delay of the connection to the server.
+func (gc *GameClient) Latency() Latency {
+	return gc.latency.Latency
 }
 
 func (gc *GameClient) AddListener(e Event, l EventListener) {
@@ -153,25 +301,5 @@ func (gc *GameClient) AddListener(e Event, l EventListener) {
 }
 
 func (gc *GameClient) World() []game.FieldPos {
-	fieldPos := make([]game.FieldPos, 0, gc.gameMap.Width()*gc.gameMap.Height())
-
-	var x, y uint16
-	for y = 1; y <= gc.gameMap.Height(); y++ {
-		for x = 1; x <= gc.gameMap.Width(); x++ {
-			pos := game.Position{Y: uint16(y), X: x}
-			if gc.gameMap.IsWall(pos) {
-				fieldPos = append(fieldPos, game.FieldPos{
-					Field:    game.FieldWall,
-					Position: pos,
-				})
-			} else {
-				fieldPos = append(fieldPos, game.FieldPos{
-					Field:    game.FieldEmpty,
-					Position: pos,
-				})
-			}
-		}
-	}
-
-	return fieldPos
+	return gc.gameMap.World()
 }
@@ -1,5 +1,11 @@
 package client
 
+import (
+	"reflect"
+
+	"github.com/apfelfrisch/gosnake/game"
+)
+
 type EventListener func(event Event)
 
 type Event interface{}
@@ -7,25 +13,61 @@ type Event interface{}
 type GameHasStarted struct{}
 type GameHasEnded struct{}
 type GameWas struct{}
-type PlayerDashed struct{}
-type PlayerCrashed struct{}
-type PlayerHasEaten struct{}
-type PlayerWalkedWall struct{}
 
+// The players of the events are indexes in the order of the profiles of the
+// payload, 0 is the own player. Spectators get them in slot order.
+
+type PlayerDashed struct {
+	Player   int
+	Position game.Position
+}
+
+type PlayerCrashed struct {
+	Player int
+	Cause  game.DeathCause
+	// Opponent is game.NoOpponent if no snake caused the crash.
+	Opponent int
+	Position game.Position
+}
+
+type PlayerHasEaten struct {
+	Player   int
+	Candy    game.CandyTpe
+	Position game.Position
+}
+
+type PlayerWalkedWall struct {
+	Player   int
+	Position game.Position
+}
+
+// PlayerUsedPerk is sent for the perks without an event of their own.
+type PlayerUsedPerk struct {
+	Player   int
+	Perk     game.PerkType
+	Position game.Position
+}
+
+type LevelHasChanged struct {
+	Level uint16
+}
+
+// EventBus calls the listeners of an event type, they are added with the
+// zero value of the type.
 type EventBus struct {
-	lst map[Event][]EventListener
+	lst map[reflect.Type][]EventListener
 }
 
 func NewEventBus() *EventBus {
-	return &EventBus{make(map[Event][]EventListener)}
+	return &EventBus{make(map[reflect.Type][]EventListener)}
 }
 
 func (m *EventBus) Add(e Event, l EventListener) {
-	m.lst[e] = append(m.lst[e], l)
+	m.lst[reflect.TypeOf(e)] = append(m.lst[reflect.TypeOf(e)], l)
 }
 
 func (m *EventBus) Dispatch(e Event) {
-	for _, listener := range m.lst[e] {
+	for _, listener := range m.lst[reflect.TypeOf(e)] {
 		listener(e)
 	}
 }
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+)
+
+// Latency is the measured delay of the connection to the server.
+type Latency struct {
+	// RTT is the smoothed round trip time, 0 until it was measured.
+	RTT time.Duration
+	// Jitter is the smoothed variation of the delay of the states.
+	Jitter time.Duration
+}
+
+// latencyMeter measures the round trip with the ack times the server
+// echoes and the jitter with the send times of the states.
+type latencyMeter struct {
+	Latency
+	echo     uint64
+	transit  time.Duration
+	measured bool
+}
+
+func (m *latencyMeter) observe(state payload.Payload, arrived time.Time) {
+	if state.EchoTime > m.echo {
+		m.echo = state.EchoTime
+		held := time.Duration(state.EchoDelay) * time.Microsecond
+		if rtt := time.Duration(clientTime(arrived)-state.EchoTime) - held; rtt > 0 {
+			if m.RTT == 0 {
+				m.RTT = rtt
+			} else {
+				m.RTT += (rtt - m.RTT) / 8
+			}
+		}
+	}
+
+	if state.ServerTime != 0 {
+		// Jitter as in RFC 3550, the clocks of client and server do not
+		// need to agree
+		transit := arrived.Sub(time.UnixMicro(state.ServerTime))
+		if m.measured {
+			m.Jitter += (abs(transit-m.transit) - m.Jitter) / 16
+		}
+		m.transit = transit
+		m.measured = true
+	}
+}
@@ -0,0 +1,374 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/proto"
+)
+
+const HANDSHAKE_REQ = '?'
+const HANDSHAKE_RESP = '!'
+const HEARTBEAT = '♥'
+
+// READY toggles if the player is ready to start the match.
+const READY = '✓'
+
+// LEAVE frees the player slot while the server waits in the lobby.
+const LEAVE = '✗'
+
+// ACK is followed by the sequence of the last decoded snapshot, so the
+// server can send deltas against it, and the client time the server echoes.
+const ACK = '↩'
+
+// INPUT is followed by the inputs the server has not acknowledged yet.
+const INPUT = '⌨'
+
+// InputResendInterval is how often unacknowledged inputs are sent again.
+const InputResendInterval = 50 * time.Millisecond
+
+// maxPendingInputs is how many unacknowledged inputs the client keeps. The
+// oldest ones are given up if the server stays silent.
+const maxPendingInputs = 32
+
+// HeartbeatInterval is how often the client tells the server it is still
+// there, so a client without key presses does not time out.
+const HeartbeatInterval = time.Second
+
+// ServerTimeout is how long the client waits for a package before it
+// considers the connection lost.
+const ServerTimeout = 2 * time.Second
+
+// RejectedError is returned by Connect if the server rejected the hello.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "server rejected the connection: " + e.Reason
+}
+
+// received is a decompressed message of the server and the time it arrived.
+type received struct {
+	data []byte
+	at   time.Time
+}
+
+// clockStart is the zero of the client time the server echoes.
+var clockStart = time.Now()
+
+// messageConn sends and receives whole messages over a network.
+type messageConn interface {
+	// ReadMessage returns the next compressed message of the server.
+	ReadMessage() ([]byte, error)
+	WriteMessage(message []byte) error
+	Close() error
+}
+
+// link is the part of a transport that does not depend on the network: the
+// handshake, heartbeats, acks and the resending of inputs.
+type link struct {
+	addr         string
+	dial         func(ctx context.Context, addr string) (messageConn, error)
+	conn         messageConn
+	input        received
+	hello        payload.Hello
+	helloMu      sync.Mutex
+	helloReply   payload.HelloReply
+	replyChan    chan payload.HelloReply
+	lastReceived atomic.Int64
+	inputChan    chan received
+	inputMu      sync.Mutex
+	inputs       []payload.Input
+	nextInput    uint32
+	sendInputs   chan struct{}
+	ackChan      chan uint32
+	stopChan     chan struct{}
+	isOpen       bool
+}
+
+func newLink(addr string, hello payload.Hello, dial func(ctx context.Context, addr string) (messageConn, error)) link {
+	return link{
+		addr:       addr,
+		dial:       dial,
+		hello:      hello,
+		replyChan:  make(chan payload.HelloReply, 1),
+		inputChan:  make(chan received, 5),
+		sendInputs: make(chan struct{}, 1),
+		ackChan:    make(chan uint32, 1),
+	}
+}
+
+func (c *link) Connect(ctx context.Context) error {
+	var err error
+	c.conn, err = c.dial(ctx, c.addr)
+	if err != nil {
+		return err
+	}
+
+	c.stopChan = make(chan struct{})
+	c.isOpen = true
+
+	// A reply of an earlier connection must not answer this hello
+	select {
+	case <-c.replyChan:
+	default:
+	}
+
+	go c.handleReading(c.conn, c.stopChan)
+	go c.handleWriting(c.conn, c.stopChan)
+
+	// Sending the token of an earlier connection reconnects into the same
+	// player slot
+	hello := c.hello
+	hello.SessionToken = c.reply().SessionToken
+
+	helloData, err := proto.Marshal(hello.ToProto())
+	if err != nil {
+		c.Disconnect()
+		return err
+	}
+	helloMessage := append([]byte(string(HANDSHAKE_REQ)), helloData...)
+
+	resend := time.NewTicker(time.Second / 5)
+	defer resend.Stop()
+
+	c.conn.WriteMessage(helloMessage)
+
+	var reply payload.HelloReply
+	for waiting := true; waiting; {
+		select {
+		case <-ctx.Done():
+			c.Disconnect()
+			return ctx.Err()
+		case <-resend.C:
+			c.conn.WriteMessage(helloMessage)
+		case reply = <-c.replyChan:
+			waiting = false
+		}
+	}
+
+	if !reply.Accepted {
+		c.Disconnect()
+		return &RejectedError{Reason: reply.Reason}
+	}
+
+	c.helloMu.Lock()
+	c.helloReply = reply
+	c.helloMu.Unlock()
+
+	return nil
+}
+
+// reply returns the hello reply of the last accepted connection.
+func (c *link) reply() payload.HelloReply {
+	c.helloMu.Lock()
+	defer c.helloMu.Unlock()
+
+	return c.helloReply
+}
+
+// PlayerIndex returns the player slot the server assigned to the client.
+func (c *link) PlayerIndex() int {
+	return c.reply().PlayerIndex
+}
+
+// Reconnect opens a new connection and asks the server for the player slot
+// of the current session.
+func (c *link) Reconnect(ctx context.Context) error {
+	c.Disconnect()
+
+	return c.Connect(ctx)
+}
+
+// IsSilent reports if the server sent nothing for ServerTimeout.
+func (c *link) IsSilent() bool {
+	return time.Since(time.Unix(0, c.lastReceived.Load())) > ServerTimeout
+}
+
+func (c *link) Disconnect() {
+	if c.isOpen {
+		close(c.stopChan)
+		c.isOpen = false
+	}
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// Leave tells the server the client is gone for good and disconnects.
+func (c *link) Leave() {
+	if c.conn != nil {
+		c.conn.WriteMessage([]byte(string(LEAVE)))
+	}
+	c.Disconnect()
+}
+
+func (c *link) Read() []byte {
+	select {
+	case value := <-c.inputChan:
+		c.input = value
+		return c.input.data
+	default:
+		return c.input.data
+	}
+}
+
+// ReceivedAt returns when the message Read returns arrived.
+func (c *link) ReceivedAt() time.Time {
+	return c.input.at
+}
+
+// Write sends a key press, stamped with the tick the client saw, and returns
+// its sequence. It is repeated until the server acknowledges it.
+func (c *link) Write(char rune, tick uint32) uint32 {
+	c.inputMu.Lock()
+	c.nextInput++
+	sequence := c.nextInput
+	c.inputs = append(c.inputs, payload.Input{Sequence: sequence, Tick: tick, Key: char})
+	if len(c.inputs) > maxPendingInputs {
+		c.inputs = c.inputs[1:]
+	}
+	c.inputMu.Unlock()
+
+	select {
+	case c.sendInputs <- struct{}{}:
+	default:
+	}
+
+	return sequence
+}
+
+// AckInputs forgets the inputs up to the sequence the server acknowledged.
+func (c *link) AckInputs(sequence uint32) {
+	c.inputMu.Lock()
+	defer c.inputMu.Unlock()
+
+	for len(c.inputs) > 0 && c.inputs[0].Sequence <= sequence {
+		c.inputs = c.inputs[1:]
+	}
+}
+
+// pendingInputs encodes the unacknowledged inputs, nil if there are none.
+func (c *link) pendingInputs() []byte {
+	c.inputMu.Lock()
+	defer c.inputMu.Unlock()
+
+	if len(c.inputs) == 0 {
+		return nil
+	}
+
+	data, err := proto.Marshal(payload.InputsToProto(c.inputs))
+	if err != nil {
+		log.Println("CLIENT: Could not encode inputs:", err)
+		return nil
+	}
+
+	return append([]byte(string(INPUT)), data...)
+}
+
+// Ack acknowledges a decoded snapshot. Only the newest unsent ack is kept.
+func (c *link) Ack(sequence uint32) {
+	select {
+	case c.ackChan <- sequence:
+	default:
+		select {
+		case <-c.ackChan:
+		default:
+		}
+		c.ackChan <- sequence
+	}
+}
+
+func (c *link) handleReading(conn messageConn, stopChan chan struct{}) {
+	for {
+		compressed, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-stopChan:
+			default:
+				log.Println("CLIENT: Connection lost:", err)
+			}
+			return
+		}
+
+		// Decompress Payload
+		decompressed, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			log.Println("Error decompressing data:", err)
+			continue
+		}
+
+		now := time.Now()
+		c.lastReceived.Store(now.UnixNano())
+
+		if isHandshakeResponse(decompressed) {
+			protoReply := &payload.ProtoHelloReply{}
+			if err := proto.Unmarshal(decompressed[1:], protoReply); err != nil {
+				log.Println("CLIENT: Invalid hello reply:", err)
+				continue
+			}
+			select {
+			case c.replyChan <- payload.HelloReplyFromProto(protoReply):
+			default:
+			}
+			continue
+		}
+
+		select {
+		// Try to write to the channel
+		case c.inputChan <- received{data: decompressed, at: now}:
+		// Otherwise clear channel
+		default:
+			<-c.inputChan
+			c.inputChan <- received{data: decompressed, at: now}
+		}
+	}
+}
+
+func (c *link) handleWriting(conn messageConn, stopChan chan struct{}) {
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+	resend := time.NewTicker(InputResendInterval)
+	defer resend.Stop()
+
+	writeInputs := func() {
+		if message := c.pendingInputs(); message != nil {
+			conn.WriteMessage(message)
+		}
+	}
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-heartbeat.C:
+			conn.WriteMessage([]byte(string(HEARTBEAT)))
+		case <-c.sendInputs:
+			writeInputs()
+		case <-resend.C:
+			writeInputs()
+		case sequence := <-c.ackChan:
+			message := binary.BigEndian.AppendUint32([]byte(string(ACK)), sequence)
+			conn.WriteMessage(binary.BigEndian.AppendUint64(message, clientTime(time.Now())))
+		}
+	}
+}
+
+// clientTime returns t in nanoseconds since clockStart.
+func clientTime(t time.Time) uint64 {
+	return uint64(t.Sub(clockStart))
+}
+
+// isHandshakeResponse tells hello replies apart from payloads. No encoded
+// payload starts with the response rune.
+func isHandshakeResponse(data []byte) bool {
+	return len(data) > 0 && data[0] == HANDSHAKE_RESP
+}
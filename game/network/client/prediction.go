@@ -0,0 +1,120 @@
+package client
+
+import (
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+)
+
+// maxPredictedTicks bounds how far the own snake runs ahead of the last
+// state, so it stops when the server goes silent.
+const maxPredictedTicks = 16
+
+// prediction runs the own snake ahead of the server with the inputs the
+// server has not applied yet, so a key press shows without waiting for the
+// round trip.
+type prediction struct {
+	inputs       []predictedInput
+	tickDuration time.Duration
+	// tick is the newest tick of the server and origin the local time
+	// its round started at, estimated from the arrival of the states.
+	tick   uint32
+	origin time.Time
+	// lead is how many ticks the own snake runs ahead of the server,
+	// measured from the ticks the server applied the inputs in.
+	lead float64
+}
+
+type predictedInput struct {
+	sequence uint32
+	key      rune
+	// sent is the server tick the client estimated when it sent the input.
+	sent float64
+}
+
+// observe reconciles the prediction with a new state of the server. The
+// inputs the state includes are dropped, the others are replayed on top of
+// it.
+func (p *prediction) observe(state payload.Payload, now time.Time) {
+	if state.GameState != game.Ongoing || state.Spectator {
+		p.inputs = nil
+		p.origin = time.Time{}
+		return
+	}
+
+	p.tickDuration = state.Rules.TickDuration()
+	if p.origin.IsZero() || state.Tick != p.tick {
+		origin := now.Add(-time.Duration(state.Tick) * p.tickDuration)
+		if p.origin.IsZero() || state.Tick < p.tick || abs(origin.Sub(p.origin)) > 2*p.tickDuration {
+			p.origin = origin
+		} else {
+			// Smooth out the jitter of the network
+			p.origin = p.origin.Add(origin.Sub(p.origin) / 8)
+		}
+		p.tick = state.Tick
+	}
+
+	for len(p.inputs) > 0 && p.inputs[0].sequence <= state.InputApplied {
+		// An input waits half a tick for the next tick on average
+		lead := max(float64(state.Tick)-p.inputs[0].sent-0.5, 0)
+		if p.lead == 0 {
+			p.lead = lead
+		} else if lead <= maxPredictedTicks {
+			p.lead += (lead - p.lead) / 4
+		}
+		p.inputs = p.inputs[1:]
+	}
+}
+
+// press remembers an input that was sent to the server.
+func (p *prediction) press(sequence uint32, key rune, now time.Time) {
+	if p.origin.IsZero() {
+		return
+	}
+
+	p.inputs = append(p.inputs, predictedInput{sequence: sequence, key: key, sent: p.serverTick(now)})
+	if len(p.inputs) > maxPendingInputs {
+		p.inputs = p.inputs[1:]
+	}
+}
+
+// predict replays the pending inputs on the own snake of the state, up to
+// the tick an input sent now would be applied in.
+func (p *prediction) predict(state payload.Payload, width, height uint16, now time.Time) game.Snake {
+	snake := state.Player
+	if p.origin.IsZero() || !snake.Alive || len(snake.Occupied) == 0 {
+		return snake
+	}
+
+	target := uint32(max(p.serverTick(now)+p.lead, 0))
+	target = min(target, state.Tick+maxPredictedTicks)
+
+	inputs := p.inputs
+	for tick := state.Tick; tick < target; tick++ {
+		// The server applies one input per tick, the first one it could
+		// have received by then
+		input := game.InputNone
+		if len(inputs) > 0 && inputs[0].sent+p.lead < float64(tick+1) {
+			input = game.Input(inputs[0].key)
+			inputs = inputs[1:]
+		}
+		snake = snake.Predict(input, width, height, state.Candies, state.Rules)
+	}
+
+	return snake
+}
+
+// serverTick estimates the current tick of the server, as far as the client
+// can see it.
+func (p *prediction) serverTick(now time.Time) float64 {
+	return float64(now.Sub(p.origin)) / float64(p.tickDuration)
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
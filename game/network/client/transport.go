@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+)
+
+// Networks a client can connect with, the first one is the default.
+var Networks = []string{"udp", "tcp", "ws"}
+
+// Transport carries the messages between a client and the server.
+type Transport interface {
+	Connect(ctx context.Context) error
+	// Reconnect opens a new connection and asks the server for the player
+	// slot of the current session.
+	Reconnect(ctx context.Context) error
+	Disconnect()
+	// Leave tells the server the client is gone for good and disconnects.
+	Leave()
+	// IsSilent reports if the server sent nothing for ServerTimeout.
+	IsSilent() bool
+	PlayerIndex() int
+
+	// Read returns the latest message of the server.
+	Read() []byte
+	// ReceivedAt returns when the message Read returns arrived.
+	ReceivedAt() time.Time
+	// Write sends a key press and returns its sequence.
+	Write(char rune, tick uint32) uint32
+	Ack(sequence uint32)
+	AckInputs(sequence uint32)
+}
+
+// NewTransport returns the transport for network, "udp", "tcp" or "ws".
+func NewTransport(network, addr string, hello payload.Hello) (Transport, error) {
+	switch network {
+	case "udp":
+		return NewUdpClient(addr, hello), nil
+	case "tcp":
+		return NewTcpClient(addr, hello), nil
+	case "ws":
+		return NewWebsocketClient(addr, hello), nil
+	default:
+		return nil, fmt.Errorf("unknown network %q", network)
+	}
+}
@@ -2,162 +2,71 @@ package client
 
 import (
 	"context"
-	"encoding/binary"
-	"io"
+	"errors"
 	"log"
 	"net"
-	"time"
 
-	"github.com/golang/snappy"
+	"github.com/apfelfrisch/gosnake/game/network/framing"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
 )
 
-const HANDSHAKE_REQ = '?'
-const HANDSHAKE_RESP = '!'
-
-func NewUdpClient(addr string) *UdpClient {
-	udpAddr, err := net.ResolveUDPAddr("udp", addr)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return &UdpClient{
-		server:     udpAddr,
-		inputChan:  make(byteBufferChan, 5),
-		outputChan: make(chan rune, 3),
-	}
+func NewUdpClient(addr string, hello payload.Hello) *UdpClient {
+	return &UdpClient{link: newLink(addr, hello, dialUdp)}
 }
 
 type UdpClient struct {
-	server        *net.UDPAddr
-	conn          *net.UDPConn
-	input         []byte
-	lastHandshake time.Time
-	inputChan     byteBufferChan
-	outputChan    chan rune
-	stopChan      chan struct{}
-	isOpen        bool
+	link
 }
 
-func (c *UdpClient) Connect(ctx context.Context) error {
-	var err error
-	c.conn, err = net.DialUDP("udp", nil, c.server)
-
-	if err != nil {
-		return err
-	}
-
-	c.stopChan = make(chan struct{})
-	c.isOpen = true
-
-	go c.handleUdpReading()
-	go c.handleUdpWriting()
-
-	beforeHandshare := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			c.Disconnect()
-			return ctx.Err()
-		default:
-			c.Write(HANDSHAKE_REQ)
-			time.Sleep(time.Second / 5)
-		}
-
-		if c.lastHandshake.After(beforeHandshare) {
-			break
-		}
-	}
-
-	return nil
+// udpConn puts the datagrams of the server back together into messages.
+type udpConn struct {
+	*net.UDPConn
+	reassembler *framing.Reassembler
+	datagram    []byte
 }
 
-func (c *UdpClient) Disconnect() {
-	if c.isOpen {
-		close(c.stopChan)
-		c.isOpen = false
-	}
-	if c.conn != nil {
-		c.conn.Close()
-		c.conn = nil
+func dialUdp(ctx context.Context, addr string) (messageConn, error) {
+	server, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (c *UdpClient) Read() []byte {
-	select {
-	case value := <-c.inputChan:
-		c.input = value[0]
-		return c.input
-	default:
-		return c.input
+	conn, err := net.DialUDP("udp", nil, server)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (c *UdpClient) Write(char rune) {
-	select {
-	// Try to write to the channel
-	case c.outputChan <- char:
-	// Otherwise clear channel
-	default:
-		<-c.outputChan
-		c.outputChan <- char
-	}
+	return &udpConn{
+		UDPConn:     conn,
+		reassembler: framing.NewReassembler(),
+		datagram:    make([]byte, framing.MaxDatagramSize),
+	}, nil
 }
 
-func (c *UdpClient) handleUdpReading() {
+func (c *udpConn) ReadMessage() ([]byte, error) {
 	for {
-		select {
-		case <-c.stopChan:
-			return
-		default:
-			var lengthBuffer [4]byte
-			n, err := io.ReadFull(c.conn, lengthBuffer[:])
-			if len(lengthBuffer[:n]) == 0 {
-				continue
-			}
-			if err != nil {
-				log.Println("UDP-CLIENT: Could not read response: ", string(lengthBuffer[:]))
-				continue
-			}
-
-			// Read Payload
-			compressed := make([]byte, binary.BigEndian.Uint32(lengthBuffer[:]))
-			_, err = io.ReadFull(c.conn, compressed)
-			if err != nil {
-				log.Println(err)
-				return
-			}
-
-			// Decompress Payload
-			decompressed, err := snappy.Decode(nil, compressed)
-			if err != nil {
-				log.Println("Error decompressing data:", err)
-				return
-			}
-
-			if len(decompressed) == 1 && string(decompressed) == string(HANDSHAKE_RESP) {
-				c.lastHandshake = time.Now()
-				continue
-			}
+		n, err := c.Read(c.datagram)
+		if errors.Is(err, net.ErrClosed) {
+			return nil, err
+		}
+		if err != nil {
+			// The server may not listen yet
+			continue
+		}
 
-			select {
-			// Try to write to the channel
-			case c.inputChan <- byteBuffer{decompressed}:
-			// Otherwise clear channel
-			default:
-				<-c.inputChan
-				c.inputChan <- byteBuffer{decompressed}
-			}
+		message, err := c.reassembler.Add(c.datagram[:n])
+		if errors.Is(err, framing.ErrCorrupt) {
+			log.Println("UDP-CLIENT: Dropped datagram:", err)
+			continue
+		}
+		if message != nil {
+			return message, nil
 		}
 	}
 }
 
-func (c *UdpClient) handleUdpWriting() {
-	for {
-		select {
-		case <-c.stopChan:
-			return
-		case message := <-c.outputChan:
-			c.conn.Write([]byte(string(message)))
-		}
-	}
+func (c *udpConn) WriteMessage(message []byte) error {
+	_, err := c.Write(message)
+
+	return err
 }
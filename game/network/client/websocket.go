@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apfelfrisch/gosnake/game/network/framing"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/coder/websocket"
+)
+
+// NewWebsocketClient connects over WebSocket, the only network of the
+// browser. The address is a host and port or a ws:// or wss:// URL.
+func NewWebsocketClient(addr string, hello payload.Hello) *WebsocketClient {
+	return &WebsocketClient{link: newLink(addr, hello, dialWebsocket)}
+}
+
+type WebsocketClient struct {
+	link
+}
+
+// websocketConn sends every message as a binary WebSocket message.
+type websocketConn struct {
+	conn *websocket.Conn
+}
+
+func dialWebsocket(ctx context.Context, addr string) (messageConn, error) {
+	if !strings.HasPrefix(addr, "ws://") && !strings.HasPrefix(addr, "wss://") {
+		addr = "ws://" + addr
+	}
+
+	conn, _, err := websocket.Dial(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(framing.MaxFrameSize)
+
+	return &websocketConn{conn: conn}, nil
+}
+
+func (c *websocketConn) ReadMessage() ([]byte, error) {
+	_, message, err := c.conn.Read(context.Background())
+
+	return message, err
+}
+
+func (c *websocketConn) WriteMessage(message []byte) error {
+	return c.conn.Write(context.Background(), websocket.MessageBinary, message)
+}
+
+func (c *websocketConn) Close() error {
+	return c.conn.CloseNow()
+}
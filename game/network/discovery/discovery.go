@@ -0,0 +1,156 @@
+// Package discovery finds game servers on the LAN. Servers with an open lobby
+// broadcast an announcement every AnnounceInterval, clients collect them with
+// a Browser.
+package discovery
+
+import (
+	"context"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"google.golang.org/protobuf/proto"
+)
+
+// Port is where clients listen for announcements.
+const Port = 1201
+
+const AnnounceInterval = time.Second
+
+// ServerTimeout is how long a server stays listed without announcing.
+const ServerTimeout = 3 * AnnounceInterval
+
+// Announce broadcasts the announcement every AnnounceInterval until ctx is
+// done. No announcement is sent while announcement returns false.
+func Announce(ctx context.Context, announcement func() (payload.Announcement, bool)) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		log.Println("DISCOVERY: Could not announce server:", err)
+		return
+	}
+	defer conn.Close()
+
+	broadcast := &net.UDPAddr{IP: net.IPv4bcast, Port: Port}
+
+	ticker := time.NewTicker(AnnounceInterval)
+	defer ticker.Stop()
+
+	for {
+		if a, ok := announcement(); ok {
+			a.ProtocolVersion = payload.ProtocolVersion
+
+			data, err := proto.Marshal(a.ToProto())
+			if err != nil {
+				log.Println("DISCOVERY: Could not encode announcement:", err)
+				return
+			}
+			if _, err := conn.WriteToUDP(data, broadcast); err != nil {
+				log.Println("DISCOVERY: Could not send announcement:", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Server is a game server found on the LAN.
+type Server struct {
+	payload.Announcement
+	// Addr of the game server as host:port.
+	Addr     string
+	lastSeen time.Time
+}
+
+// Browser collects the announcements of the servers on the LAN.
+type Browser struct {
+	conn    *net.UDPConn
+	mu      sync.Mutex
+	servers map[string]Server
+}
+
+// Browse listens for announcements. The port is shared, so several clients
+// on one host can browse at once.
+func Browse() (*Browser, error) {
+	listenConfig := net.ListenConfig{Control: reuseAddr}
+	conn, err := listenConfig.ListenPacket(context.Background(), "udp4", ":"+strconv.Itoa(Port))
+	if err != nil {
+		return nil, err
+	}
+
+	browser := &Browser{
+		conn:    conn.(*net.UDPConn),
+		servers: make(map[string]Server),
+	}
+	go browser.read()
+
+	return browser, nil
+}
+
+// Servers returns the servers that announced themselves within
+// ServerTimeout, sorted by name.
+func (b *Browser) Servers() []Server {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	servers := make([]Server, 0, len(b.servers))
+	for addr, server := range b.servers {
+		if time.Since(server.lastSeen) > ServerTimeout {
+			delete(b.servers, addr)
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	slices.SortFunc(servers, func(a, b Server) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Addr, b.Addr)
+	})
+
+	return servers
+}
+
+func (b *Browser) Close() {
+	b.conn.Close()
+}
+
+func (b *Browser) read() {
+	buffer := make([]byte, 512)
+
+	for {
+		n, addr, err := b.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		protoAnnouncement := &payload.ProtoAnnouncement{}
+		if err := proto.Unmarshal(buffer[:n], protoAnnouncement); err != nil {
+			continue
+		}
+
+		announcement := payload.AnnouncementFromProto(protoAnnouncement)
+		if announcement.ProtocolVersion != payload.ProtocolVersion {
+			continue
+		}
+
+		serverAddr := net.JoinHostPort(addr.IP.String(), strconv.Itoa(announcement.Port))
+
+		b.mu.Lock()
+		b.servers[serverAddr] = Server{
+			Announcement: announcement,
+			Addr:         serverAddr,
+			lastSeen:     time.Now(),
+		}
+		b.mu.Unlock()
+	}
+}
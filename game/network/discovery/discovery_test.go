@@ -0,0 +1,17 @@
+package discovery
+
+import "testing"
+
+func TestBrowseTwiceOnOneHost(t *testing.T) {
+	first, err := Browse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := Browse()
+	if err != nil {
+		t.Fatal("a second browser could not listen:", err)
+	}
+	second.Close()
+}
@@ -0,0 +1,11 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package discovery
+
+import "syscall"
+
+// reuseAddr leaves the socket as it is, only one client per host can listen
+// for announcements.
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	return nil
+}
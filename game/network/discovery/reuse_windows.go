@@ -0,0 +1,16 @@
+package discovery
+
+import "syscall"
+
+// reuseAddr lets several clients on one host listen for announcements.
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	var err error
+	controlErr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(syscall.Handle(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+
+	return err
+}
@@ -0,0 +1,171 @@
+// Package framing sends messages over UDP and streams. Every datagram
+// carries a header with the message id, its fragment index and a checksum,
+// so messages larger than MaxDatagramSize are split and put back together,
+// and corrupt, stale or duplicate datagrams are dropped. On streams every
+// message is prefixed with its length.
+package framing
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+)
+
+// MaxDatagramSize stays below the MTU of common links, so datagrams are not
+// fragmented by IP.
+const MaxDatagramSize = 1200
+
+// MaxFragments is how many datagrams a message may be split into.
+const MaxFragments = 64
+
+// FragmentTimeout is how long the fragments of an incomplete message are
+// kept.
+const FragmentTimeout = time.Second
+
+// maxPending is how many incomplete messages are kept at once.
+const maxPending = 4
+
+// headerSize is the id, fragment index, fragment count and checksum.
+const headerSize = 12
+
+const maxBodySize = MaxDatagramSize - headerSize
+
+var ErrCorrupt = errors.New("corrupt datagram")
+
+// ErrStale is returned for fragments of a message older than the last
+// complete one.
+var ErrStale = errors.New("stale datagram")
+
+// Encode splits a message into datagrams. Ids must increase with every
+// message, the receiver drops messages older than the last complete one.
+func Encode(id uint32, message []byte) ([][]byte, error) {
+	count := max(1, (len(message)+maxBodySize-1)/maxBodySize)
+	if count > MaxFragments {
+		return nil, fmt.Errorf("message of %d bytes exceeds %d fragments", len(message), MaxFragments)
+	}
+
+	datagrams := make([][]byte, count)
+	for index := range datagrams {
+		body := message[index*maxBodySize : min(len(message), (index+1)*maxBodySize)]
+
+		datagram := make([]byte, headerSize, headerSize+len(body))
+		binary.BigEndian.PutUint32(datagram[0:], id)
+		binary.BigEndian.PutUint16(datagram[4:], uint16(index))
+		binary.BigEndian.PutUint16(datagram[6:], uint16(count))
+		datagram = append(datagram, body...)
+		binary.BigEndian.PutUint32(datagram[8:], checksum(datagram))
+
+		datagrams[index] = datagram
+	}
+
+	return datagrams, nil
+}
+
+// Reassembler collects the datagrams of one sender.
+type Reassembler struct {
+	last    uint32
+	pending map[uint32]*partial
+}
+
+type partial struct {
+	fragments [][]byte
+	received  int
+	started   time.Time
+}
+
+func NewReassembler() *Reassembler {
+	return &Reassembler{pending: make(map[uint32]*partial)}
+}
+
+// Add takes a datagram and returns the message once all of its fragments
+// arrived, nil while fragments are missing.
+func (r *Reassembler) Add(datagram []byte) ([]byte, error) {
+	if len(datagram) < headerSize || binary.BigEndian.Uint32(datagram[8:]) != checksum(datagram) {
+		return nil, ErrCorrupt
+	}
+
+	id := binary.BigEndian.Uint32(datagram[0:])
+	index := int(binary.BigEndian.Uint16(datagram[4:]))
+	count := int(binary.BigEndian.Uint16(datagram[6:]))
+	if count == 0 || count > MaxFragments || index >= count {
+		return nil, ErrCorrupt
+	}
+	if r.last != 0 && id <= r.last {
+		return nil, ErrStale
+	}
+
+	body := datagram[headerSize:]
+	if count == 1 {
+		r.complete(id)
+		return append([]byte{}, body...), nil
+	}
+
+	r.expire()
+
+	part, ok := r.pending[id]
+	if !ok {
+		if len(r.pending) == maxPending {
+			delete(r.pending, r.oldest())
+		}
+		part = &partial{fragments: make([][]byte, count), started: time.Now()}
+		r.pending[id] = part
+	}
+	if len(part.fragments) != count {
+		return nil, ErrCorrupt
+	}
+	if part.fragments[index] != nil {
+		return nil, nil
+	}
+
+	part.fragments[index] = append([]byte(nil), body...)
+	part.received++
+	if part.received < count {
+		return nil, nil
+	}
+
+	var message []byte
+	for _, fragment := range part.fragments {
+		message = append(message, fragment...)
+	}
+	r.complete(id)
+
+	return message, nil
+}
+
+// complete drops the message and every older one.
+func (r *Reassembler) complete(id uint32) {
+	r.last = id
+	for pendingID := range r.pending {
+		if pendingID <= id {
+			delete(r.pending, pendingID)
+		}
+	}
+}
+
+func (r *Reassembler) expire() {
+	for id, part := range r.pending {
+		if time.Since(part.started) > FragmentTimeout {
+			delete(r.pending, id)
+		}
+	}
+}
+
+func (r *Reassembler) oldest() uint32 {
+	var oldest uint32
+	for id := range r.pending {
+		if oldest == 0 || id < oldest {
+			oldest = id
+		}
+	}
+
+	return oldest
+}
+
+// checksum covers the datagram without its checksum field.
+func checksum(datagram []byte) uint32 {
+	crc := crc32.ChecksumIEEE(datagram[:8])
+
+	return crc32.Update(crc, crc32.IEEETable, datagram[headerSize:])
+}
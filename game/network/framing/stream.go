@@ -0,0 +1,38 @@
+package framing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MaxFrameSize is the largest message read from a stream.
+const MaxFrameSize = 1 << 20
+
+// WriteFrame writes the message prefixed with its length in a single write.
+func WriteFrame(w io.Writer, message []byte) error {
+	frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(message)), uint32(len(message)))
+	_, err := w.Write(append(frame, message...))
+
+	return err
+}
+
+// ReadFrame reads the next message written with WriteFrame.
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(length[:])
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds %d bytes", size, MaxFrameSize)
+	}
+
+	message := make([]byte, size)
+	if _, err := io.ReadFull(r, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
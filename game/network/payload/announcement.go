@@ -0,0 +1,41 @@
+package payload
+
+// Announcement tells clients on the LAN about a server with an open lobby.
+type Announcement struct {
+	ProtocolVersion uint32
+	Name            string
+	// Port of the game server, the host is the sender of the announcement.
+	Port int
+	// Network the clients connect with, "udp" or "tcp".
+	Network  string
+	MapName  string
+	Rules    string
+	Players  int
+	Capacity int
+}
+
+func AnnouncementFromProto(protoAnnouncement *ProtoAnnouncement) Announcement {
+	return Announcement{
+		ProtocolVersion: protoAnnouncement.ProtocolVersion,
+		Name:            protoAnnouncement.Name,
+		Port:            int(protoAnnouncement.Port),
+		Network:         protoAnnouncement.Network,
+		MapName:         protoAnnouncement.MapName,
+		Rules:           protoAnnouncement.Rules,
+		Players:         int(protoAnnouncement.Players),
+		Capacity:        int(protoAnnouncement.Capacity),
+	}
+}
+
+func (announcement Announcement) ToProto() *ProtoAnnouncement {
+	return &ProtoAnnouncement{
+		ProtocolVersion: announcement.ProtocolVersion,
+		Name:            announcement.Name,
+		Port:            uint32(announcement.Port),
+		Network:         announcement.Network,
+		MapName:         announcement.MapName,
+		Rules:           announcement.Rules,
+		Players:         uint32(announcement.Players),
+		Capacity:        uint32(announcement.Capacity),
+	}
+}
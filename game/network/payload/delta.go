@@ -0,0 +1,151 @@
+package payload
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+// SnapshotHistory is how many snapshots the server and the client keep to
+// encode and decode deltas.
+const SnapshotHistory = 64
+
+// ToDeltaProto encodes the payload like ToProto, but only with the changes
+// since base, the last snapshot the client acknowledged. The snake bodies
+// only hold the added positions, the map, rules, profiles and perks are left
+// out if they did not change. Snakes missing in base are sent in full.
+func (payload Payload) ToDeltaProto(base Payload) *ProtoPayload {
+	protoPayload := payload.ToProto()
+	protoPayload.Baseline = base.Sequence
+
+	if bytes.Equal(payload.Map, base.Map) {
+		protoPayload.Map = nil
+		protoPayload.MapUnchanged = true
+	}
+	if payload.Rules == base.Rules {
+		protoPayload.Rules = nil
+	}
+	if slices.Equal(payload.Profiles, base.Profiles) {
+		protoPayload.Profiles = nil
+		protoPayload.ProfilesUnchanged = true
+	}
+	candiesDeltaToProto(protoPayload, payload.Candies, base.Candies)
+
+	snakeDeltaToProto(protoPayload.Player, payload.Player, base.Player)
+	for i, opponent := range payload.Opponents {
+		snakeDeltaToProto(protoPayload.Opponents[i], opponent, snakeAt(base.Opponents, i))
+	}
+	for i, snake := range payload.Snakes {
+		snakeDeltaToProto(protoPayload.Snakes[i], snake, snakeAt(base.Snakes, i))
+	}
+
+	return protoPayload
+}
+
+// PayloadFromDeltaProto decodes a payload encoded with ToDeltaProto. base
+// must be the snapshot with the baseline sequence.
+func PayloadFromDeltaProto(protoPayload *ProtoPayload, base Payload) Payload {
+	payload := PayloadFromProto(protoPayload)
+
+	if protoPayload.MapUnchanged {
+		payload.Map = base.Map
+	}
+	if protoPayload.Rules == nil {
+		payload.Rules = base.Rules
+	}
+	if protoPayload.ProfilesUnchanged {
+		payload.Profiles = base.Profiles
+	}
+	payload.Candies = patchCandies(base.Candies, payload.Candies, protoPayload.RemovedCandies)
+
+	patchSnake(&payload.Player, base.Player, protoPayload.GetPlayer())
+	for i := range payload.Opponents {
+		patchSnake(&payload.Opponents[i], snakeAt(base.Opponents, i), protoPayload.Opponents[i])
+	}
+	for i := range payload.Snakes {
+		patchSnake(&payload.Snakes[i], snakeAt(base.Snakes, i), protoPayload.Snakes[i])
+	}
+
+	return payload
+}
+
+func snakeAt(snakes []game.Snake, i int) game.Snake {
+	if i >= len(snakes) {
+		return game.Snake{}
+	}
+
+	return snakes[i]
+}
+
+// snakeDeltaToProto replaces the body of protoSnake with the positions added
+// to the body of base. A moving snake drops its tail and adds a head, so the
+// new body starts with the end of the old one.
+func snakeDeltaToProto(protoSnake *ProtoSnake, snake game.Snake, base game.Snake) {
+	body := snake.Occupied
+	added := len(body)
+	for kept := min(len(body), len(base.Occupied)); kept > 0; kept-- {
+		if slices.Equal(body[:kept], base.Occupied[len(base.Occupied)-kept:]) {
+			added = len(body) - kept
+			break
+		}
+	}
+
+	protoSnake.Occupied = protoSnake.Occupied[len(body)-added:]
+	protoSnake.Length = uint32(len(body))
+
+	if maps.Equal(snake.Perks, base.Perks) {
+		protoSnake.Perks = nil
+		protoSnake.PerksUnchanged = true
+	}
+}
+
+// patchSnake completes a snake decoded from a delta with its baseline snake.
+func patchSnake(snake *game.Snake, base game.Snake, protoSnake *ProtoSnake) {
+	snake.Occupied = patchBody(base.Occupied, snake.Occupied, protoSnake.GetLength())
+	if protoSnake.GetPerksUnchanged() {
+		snake.Perks = maps.Clone(base.Perks)
+	}
+}
+
+// candiesDeltaToProto replaces the candies of protoPayload with the ones
+// missing in base and lists the positions of the base candies that are gone.
+func candiesDeltaToProto(protoPayload *ProtoPayload, candies []game.Candy, base []game.Candy) {
+	protoPayload.Candies = nil
+	for _, candy := range candies {
+		if !slices.Contains(base, candy) {
+			protoPayload.Candies = append(protoPayload.Candies, candyToProto(candy))
+		}
+	}
+
+	for _, candy := range base {
+		if !slices.Contains(candies, candy) {
+			protoPayload.RemovedCandies = append(protoPayload.RemovedCandies, positionToProto(candy.Position))
+		}
+	}
+}
+
+// patchCandies removes the candies at the removed positions from the base
+// candies and appends the added ones.
+func patchCandies(base []game.Candy, added []game.Candy, removed []*ProtoPosition) []game.Candy {
+	candies := make([]game.Candy, 0, len(base)+len(added))
+	for _, candy := range base {
+		if !slices.ContainsFunc(removed, func(pos *ProtoPosition) bool { return positionFromProto(pos) == candy.Position }) {
+			candies = append(candies, candy)
+		}
+	}
+
+	return append(candies, added...)
+}
+
+// patchBody appends the added positions to the base body and cuts it down to
+// length from the tail.
+func patchBody(base []game.Position, added []game.Position, length uint32) []game.Position {
+	body := append(slices.Clone(base), added...)
+	if int(length) > len(body) {
+		return body
+	}
+
+	return body[len(body)-int(length):]
+}
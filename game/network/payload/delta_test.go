@@ -0,0 +1,94 @@
+package payload
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apfelfrisch/gosnake/game"
+	"google.golang.org/protobuf/proto"
+)
+
+func testPayload(sequence uint32, head uint16, candies []game.Candy) Payload {
+	snake := func(x uint16) game.Snake {
+		return game.Snake{
+			Occupied:  []game.Position{{X: x, Y: head}, {X: x, Y: head + 1}, {X: x, Y: head + 2}},
+			Direction: game.South,
+			Lives:     3,
+			Alive:     true,
+			Perks:     game.Perks{game.PerkTypeDash: {Usages: 2}},
+		}
+	}
+
+	return Payload{
+		Sequence:  sequence,
+		GameState: game.Ongoing,
+		Candies:   candies,
+		Player:    snake(5),
+		Opponents: []game.Snake{snake(10), snake(15)},
+		Map:       []byte("XXXX\nX..X\nXXXX\n"),
+		Rules:     game.DefaultRules(),
+		Profiles:  []Profile{{Name: "A"}, {Name: "B"}, {Name: "C"}},
+	}
+}
+
+func TestDeltaRoundTrip(t *testing.T) {
+	candies := []game.Candy{game.NewCandyGrow(game.Position{X: 3, Y: 3})}
+	base := testPayload(1, 2, candies)
+	next := testPayload(2, 3, candies)
+
+	decodedBase := PayloadFromProto(base.ToProto())
+	delta := next.ToDeltaProto(base)
+
+	if delta.Baseline != base.Sequence {
+		t.Fatalf("got baseline %d, expected %d", delta.Baseline, base.Sequence)
+	}
+	if len(delta.Player.Occupied) != 1 || len(delta.Opponents[1].Occupied) != 1 {
+		t.Fatal("the delta does not hold only the new heads")
+	}
+
+	decoded := PayloadFromDeltaProto(delta, decodedBase)
+	expected := PayloadFromProto(next.ToProto())
+
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Fatalf("decoded delta differs:\n%+v\n%+v", decoded, expected)
+	}
+}
+
+func TestDeltaLeavesOutUnchanged(t *testing.T) {
+	base := testPayload(1, 2, []game.Candy{
+		game.NewCandyGrow(game.Position{X: 3, Y: 3}),
+		game.NewCandyGrow(game.Position{X: 7, Y: 7}),
+	})
+	next := testPayload(2, 3, []game.Candy{
+		game.NewCandyGrow(game.Position{X: 7, Y: 7}),
+		game.NewCandyGrow(game.Position{X: 9, Y: 9}),
+	})
+	next.Opponents[1].Perks = game.Perks{game.PerkTypeDash: {Usages: 1}}
+
+	decodedBase := PayloadFromProto(base.ToProto())
+	delta := next.ToDeltaProto(base)
+
+	if delta.Map != nil || delta.Rules != nil || delta.Profiles != nil {
+		t.Fatal("the delta holds the unchanged map, rules or profiles")
+	}
+	if len(delta.Candies) != 1 || len(delta.RemovedCandies) != 1 {
+		t.Fatalf("got %d added and %d removed candies, expected 1 and 1", len(delta.Candies), len(delta.RemovedCandies))
+	}
+	if delta.Player.Perks != nil || delta.Opponents[1].Perks == nil {
+		t.Fatal("the delta does not hold exactly the changed perks")
+	}
+	if full := proto.Size(next.ToProto()); proto.Size(delta) >= full/2 {
+		t.Fatalf("the delta has %d bytes, the keyframe %d", proto.Size(delta), full)
+	}
+
+	decoded := PayloadFromDeltaProto(delta, decodedBase)
+	expected := PayloadFromProto(next.ToProto())
+	expected.Candies = decoded.Candies
+
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Fatalf("decoded delta differs:\n%+v\n%+v", decoded, expected)
+	}
+	if !reflect.DeepEqual(decoded.Candies, []game.Candy{next.Candies[0], next.Candies[1]}) {
+		t.Fatalf("got candies %v, expected %v", decoded.Candies, next.Candies)
+	}
+}
@@ -0,0 +1,75 @@
+package payload
+
+import "github.com/apfelfrisch/gosnake/game"
+
+// Event is a game event, numbered so a client dispatches every event once.
+// The players of an event are indexes in the order of the profiles.
+type Event struct {
+	Sequence uint32
+	game.Event
+}
+
+func eventToProto(event Event) *ProtoEvent {
+	protoEvent := &ProtoEvent{Sequence: event.Sequence}
+
+	switch e := event.Event.(type) {
+	case game.PlayerDied:
+		protoEvent.Type = ProtoEventType_PROTO_EVENT_TYPE_PLAYER_DIED
+		protoEvent.Player = uint32(e.Player)
+		protoEvent.Position = positionToProto(e.Position)
+		protoEvent.Cause = ProtoDeathCause(e.Cause)
+		protoEvent.Opponent = int32(e.Opponent)
+	case game.CandyEaten:
+		protoEvent.Type = ProtoEventType_PROTO_EVENT_TYPE_CANDY_EATEN
+		protoEvent.Player = uint32(e.Player)
+		protoEvent.Position = positionToProto(e.Position)
+		protoEvent.Candy = ProtoCandyType(e.Candy)
+	case game.PerkUsed:
+		protoEvent.Type = ProtoEventType_PROTO_EVENT_TYPE_PERK_USED
+		protoEvent.Player = uint32(e.Player)
+		protoEvent.Position = positionToProto(e.Position)
+		protoEvent.Perk = ProtoPerkType(e.Perk)
+	case game.LevelChanged:
+		protoEvent.Type = ProtoEventType_PROTO_EVENT_TYPE_LEVEL_CHANGED
+		protoEvent.Level = uint32(e.Level)
+	}
+
+	return protoEvent
+}
+
+// eventFromProto returns false for event types of newer servers.
+func eventFromProto(protoEvent *ProtoEvent) (Event, bool) {
+	event := Event{Sequence: protoEvent.Sequence}
+	position := game.Position{}
+	if protoEvent.Position != nil {
+		position = positionFromProto(protoEvent.Position)
+	}
+
+	switch protoEvent.Type {
+	case ProtoEventType_PROTO_EVENT_TYPE_PLAYER_DIED:
+		event.Event = game.PlayerDied{
+			Player:   int(protoEvent.Player),
+			Cause:    game.DeathCause(protoEvent.Cause),
+			Opponent: int(protoEvent.Opponent),
+			Position: position,
+		}
+	case ProtoEventType_PROTO_EVENT_TYPE_CANDY_EATEN:
+		event.Event = game.CandyEaten{
+			Player:   int(protoEvent.Player),
+			Candy:    game.CandyTpe(protoEvent.Candy),
+			Position: position,
+		}
+	case ProtoEventType_PROTO_EVENT_TYPE_PERK_USED:
+		event.Event = game.PerkUsed{
+			Player:   int(protoEvent.Player),
+			Perk:     game.PerkType(protoEvent.Perk),
+			Position: position,
+		}
+	case ProtoEventType_PROTO_EVENT_TYPE_LEVEL_CHANGED:
+		event.Event = game.LevelChanged{Level: uint16(protoEvent.Level)}
+	default:
+		return Event{}, false
+	}
+
+	return event, true
+}
@@ -0,0 +1,94 @@
+package payload
+
+// ProtocolVersion is raised with every change of the messages between client
+// and server that older clients can not read.
+const ProtocolVersion = 11
+
+// MaxNameLength is the maximum number of characters of a player name.
+const MaxNameLength = 16
+
+// Profile is how a player wants to appear to the other players.
+type Profile struct {
+	Name string `json:"n"`
+	// Color is the preferred snake color as 0xRRGGBB, 0 if the player has none.
+	Color uint32 `json:"c"`
+}
+
+// Hello is the first message of a client. A client sends the session token
+// of an earlier connection to reconnect into its player slot.
+type Hello struct {
+	ProtocolVersion uint32
+	Profile         Profile
+	SessionToken    string
+	// Spectator watches the game without taking a player slot.
+	Spectator bool
+}
+
+type HelloReply struct {
+	ProtocolVersion uint32
+	Accepted        bool
+	// Reason tells the player why the server rejected the hello.
+	Reason       string
+	SessionToken string
+	PlayerIndex  int
+}
+
+func NewHello(profile Profile, sessionToken string) Hello {
+	return Hello{
+		ProtocolVersion: ProtocolVersion,
+		Profile:         profile,
+		SessionToken:    sessionToken,
+	}
+}
+
+func HelloFromProto(protoHello *ProtoHello) Hello {
+	return Hello{
+		ProtocolVersion: protoHello.GetProtocolVersion(),
+		Profile:         profileFromProto(protoHello.GetProfile()),
+		SessionToken:    protoHello.GetSessionToken(),
+		Spectator:       protoHello.GetSpectator(),
+	}
+}
+
+func (hello Hello) ToProto() *ProtoHello {
+	return &ProtoHello{
+		ProtocolVersion: hello.ProtocolVersion,
+		Profile:         profileToProto(hello.Profile),
+		SessionToken:    hello.SessionToken,
+		Spectator:       hello.Spectator,
+	}
+}
+
+func HelloReplyFromProto(protoReply *ProtoHelloReply) HelloReply {
+	return HelloReply{
+		ProtocolVersion: protoReply.GetProtocolVersion(),
+		Accepted:        protoReply.GetAccepted(),
+		Reason:          protoReply.GetReason(),
+		SessionToken:    protoReply.GetSessionToken(),
+		PlayerIndex:     int(protoReply.GetPlayerIndex()),
+	}
+}
+
+func (reply HelloReply) ToProto() *ProtoHelloReply {
+	return &ProtoHelloReply{
+		ProtocolVersion: reply.ProtocolVersion,
+		Accepted:        reply.Accepted,
+		Reason:          reply.Reason,
+		SessionToken:    reply.SessionToken,
+		PlayerIndex:     uint32(reply.PlayerIndex),
+	}
+}
+
+func profileToProto(profile Profile) *ProtoProfile {
+	return &ProtoProfile{
+		Name:  profile.Name,
+		Color: profile.Color,
+	}
+}
+
+func profileFromProto(protoProfile *ProtoProfile) Profile {
+	return Profile{
+		Name:  protoProfile.GetName(),
+		Color: protoProfile.GetColor(),
+	}
+}
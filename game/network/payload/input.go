@@ -0,0 +1,36 @@
+package payload
+
+// Input is a key press of a client, numbered so the server applies the
+// inputs in order and can acknowledge them.
+type Input struct {
+	Sequence uint32
+	// Tick the client saw when the key was pressed.
+	Tick uint32
+	Key  rune
+}
+
+func InputsFromProto(protoInputs *ProtoInputs) []Input {
+	inputs := make([]Input, len(protoInputs.Inputs))
+	for i, protoInput := range protoInputs.Inputs {
+		inputs[i] = Input{
+			Sequence: protoInput.Sequence,
+			Tick:     protoInput.Tick,
+			Key:      rune(protoInput.Key),
+		}
+	}
+
+	return inputs
+}
+
+func InputsToProto(inputs []Input) *ProtoInputs {
+	protoInputs := make([]*ProtoInput, len(inputs))
+	for i, input := range inputs {
+		protoInputs[i] = &ProtoInput{
+			Sequence: input.Sequence,
+			Tick:     input.Tick,
+			Key:      uint32(input.Key),
+		}
+	}
+
+	return &ProtoInputs{Inputs: protoInputs}
+}
@@ -0,0 +1,85 @@
+package payload
+
+// Lobby lists the player slots while the server waits for the match to start.
+type Lobby struct {
+	Slots []LobbySlot `json:"sl"`
+	// Own is the slot of the receiving client, -1 for spectators.
+	Own     int    `json:"ow"`
+	MapName string `json:"mn"`
+}
+
+type LobbySlot struct {
+	Profile   Profile `json:"pf"`
+	Connected bool    `json:"co"`
+	Ready     bool    `json:"re"`
+	Bot       bool    `json:"bo"`
+	Team      uint8   `json:"tm"`
+}
+
+// Joined reports if every client slot is taken.
+func (lobby Lobby) Joined() bool {
+	for _, slot := range lobby.Slots {
+		if !slot.Bot && !slot.Connected {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Ready reports if every client is ready to start.
+func (lobby Lobby) Ready() bool {
+	for _, slot := range lobby.Slots {
+		if !slot.Bot && !slot.Ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+func lobbyFromProto(protoLobby *ProtoLobby) *Lobby {
+	if protoLobby == nil {
+		return nil
+	}
+
+	slots := make([]LobbySlot, len(protoLobby.Slots))
+	for i, protoSlot := range protoLobby.Slots {
+		slots[i] = LobbySlot{
+			Profile:   profileFromProto(protoSlot.Profile),
+			Connected: protoSlot.Connected,
+			Ready:     protoSlot.Ready,
+			Bot:       protoSlot.Bot,
+			Team:      uint8(protoSlot.Team),
+		}
+	}
+
+	return &Lobby{
+		Slots:   slots,
+		Own:     int(protoLobby.Own),
+		MapName: protoLobby.MapName,
+	}
+}
+
+func lobbyToProto(lobby *Lobby) *ProtoLobby {
+	if lobby == nil {
+		return nil
+	}
+
+	slots := make([]*ProtoLobbySlot, len(lobby.Slots))
+	for i, slot := range lobby.Slots {
+		slots[i] = &ProtoLobbySlot{
+			Profile:   profileToProto(slot.Profile),
+			Connected: slot.Connected,
+			Ready:     slot.Ready,
+			Bot:       slot.Bot,
+			Team:      uint32(slot.Team),
+		}
+	}
+
+	return &ProtoLobby{
+		Slots:   slots,
+		Own:     int32(lobby.Own),
+		MapName: lobby.MapName,
+	}
+}
@@ -8,6 +8,37 @@ type Payload struct {
 	Candies   []game.Candy   `json:"ca"`
 	Player    game.Snake     `json:"pl"`
 	Opponents []game.Snake   `json:"op"`
+	Map       []byte         `json:"mp"`
+	Rules     game.Rules     `json:"ru"`
+	Shrink    uint16         `json:"sh"`
+	// Profiles of the player, followed by the opponents.
+	Profiles []Profile `json:"pf"`
+	// Lobby is nil once the match has started.
+	Lobby *Lobby `json:"lb"`
+	// Spectators get every snake in slot order instead of Player and
+	// Opponents.
+	Spectator bool         `json:"sp"`
+	Snakes    []game.Snake `json:"sn"`
+	// Sequence numbers the snapshots of a server, starting at 1.
+	Sequence uint32 `json:"sq"`
+	// InputAck is the sequence of the last input the server got from the
+	// receiving client.
+	InputAck uint32 `json:"ia"`
+	// InputApplied is the sequence of the last input of the receiving
+	// client the state includes.
+	InputApplied uint32 `json:"ap"`
+	Tick         uint32 `json:"tk"`
+	// ServerTick counts the steps of the server, a resent state has the
+	// same one.
+	ServerTick uint32 `json:"st"`
+	// ServerTime is the Unix time in microseconds the state was sent at.
+	ServerTime int64 `json:"ti"`
+	// EchoTime is the client time of the last ack and EchoDelay the
+	// microseconds the server held it.
+	EchoTime  uint64 `json:"et"`
+	EchoDelay uint32 `json:"ed"`
+	// Events the client has not acknowledged yet, oldest first.
+	Events []Event `json:"ev"`
 }
 
 func PayloadFromProto(protoPayload *ProtoPayload) Payload {
@@ -21,12 +52,45 @@ func PayloadFromProto(protoPayload *ProtoPayload) Payload {
 		opponents[i] = snakeFromProto(protoOpponent)
 	}
 
+	profiles := make([]Profile, len(protoPayload.Profiles))
+	for i, protoProfile := range protoPayload.Profiles {
+		profiles[i] = profileFromProto(protoProfile)
+	}
+
+	snakes := make([]game.Snake, len(protoPayload.Snakes))
+	for i, protoSnake := range protoPayload.Snakes {
+		snakes[i] = snakeFromProto(protoSnake)
+	}
+
+	events := make([]Event, 0, len(protoPayload.Events))
+	for _, protoEvent := range protoPayload.Events {
+		if event, ok := eventFromProto(protoEvent); ok {
+			events = append(events, event)
+		}
+	}
+
 	return Payload{
-		MapLevel:  uint16(protoPayload.MapLevel),
-		GameState: game.GameState(protoPayload.GameState),
-		Candies:   candies,
-		Player:    snakeFromProto(protoPayload.Player),
-		Opponents: opponents,
+		MapLevel:     uint16(protoPayload.MapLevel),
+		GameState:    game.GameState(protoPayload.GameState),
+		Candies:      candies,
+		Player:       snakeFromProto(protoPayload.Player),
+		Opponents:    opponents,
+		Map:          protoPayload.Map,
+		Rules:        rulesFromProto(protoPayload.Rules),
+		Shrink:       uint16(protoPayload.Shrink),
+		Profiles:     profiles,
+		Lobby:        lobbyFromProto(protoPayload.Lobby),
+		Spectator:    protoPayload.Spectator,
+		Snakes:       snakes,
+		Sequence:     protoPayload.Sequence,
+		InputAck:     protoPayload.InputAck,
+		InputApplied: protoPayload.InputApplied,
+		Tick:         protoPayload.Tick,
+		ServerTick:   protoPayload.ServerTick,
+		ServerTime:   protoPayload.ServerTime,
+		EchoTime:     protoPayload.EchoTime,
+		EchoDelay:    protoPayload.EchoDelay,
+		Events:       events,
 	}
 }
 
@@ -41,12 +105,43 @@ func (payload Payload) ToProto() *ProtoPayload {
 		opponents[i] = snakeToProto(opponent)
 	}
 
+	profiles := make([]*ProtoProfile, len(payload.Profiles))
+	for i, profile := range payload.Profiles {
+		profiles[i] = profileToProto(profile)
+	}
+
+	snakes := make([]*ProtoSnake, len(payload.Snakes))
+	for i, snake := range payload.Snakes {
+		snakes[i] = snakeToProto(snake)
+	}
+
+	events := make([]*ProtoEvent, len(payload.Events))
+	for i, event := range payload.Events {
+		events[i] = eventToProto(event)
+	}
+
 	return &ProtoPayload{
-		MapLevel:  uint32(payload.MapLevel),
-		GameState: ProtoGameState(payload.GameState),
-		Candies:   candies,
-		Player:    snakeToProto(payload.Player),
-		Opponents: opponents,
+		MapLevel:     uint32(payload.MapLevel),
+		GameState:    ProtoGameState(payload.GameState),
+		Candies:      candies,
+		Player:       snakeToProto(payload.Player),
+		Opponents:    opponents,
+		Map:          payload.Map,
+		Rules:        rulesToProto(payload.Rules),
+		Shrink:       uint32(payload.Shrink),
+		Profiles:     profiles,
+		Lobby:        lobbyToProto(payload.Lobby),
+		Spectator:    payload.Spectator,
+		Snakes:       snakes,
+		Sequence:     payload.Sequence,
+		InputAck:     payload.InputAck,
+		InputApplied: payload.InputApplied,
+		Tick:         payload.Tick,
+		ServerTick:   payload.ServerTick,
+		ServerTime:   payload.ServerTime,
+		EchoTime:     payload.EchoTime,
+		EchoDelay:    payload.EchoDelay,
+		Events:       events,
 	}
 }
 
@@ -93,6 +188,7 @@ func snakeToProto(snake game.Snake) *ProtoSnake {
 		perks[int32(perkType)] = &ProtoPerk{
 			Type:   ProtoPerkType(perkType),
 			Usages: uint32(perk.Usages),
+			Ticks:  uint32(perk.Ticks),
 		}
 	}
 
@@ -107,6 +203,8 @@ func snakeToProto(snake game.Snake) *ProtoSnake {
 		Occupied:  occupied,
 		Direction: ProtoDirection(snake.Direction),
 		Points:    uint32(snake.Points),
+		Alive:     snake.Alive,
+		Team:      uint32(snake.Team),
 		// Grows:     uint32(snake.grows),
 	}
 }
@@ -115,7 +213,7 @@ func snakeToProto(snake game.Snake) *ProtoSnake {
 func snakeFromProto(protoSnake *ProtoSnake) game.Snake {
 	perks := make(game.Perks)
 	for perkType, perk := range protoSnake.Perks {
-		perks[game.PerkType(perkType)] = game.Perk{Usages: uint16(perk.Usages)}
+		perks[game.PerkType(perkType)] = game.Perk{Usages: uint16(perk.Usages), Ticks: uint16(perk.Ticks)}
 	}
 
 	occupied := make([]game.Position, len(protoSnake.Occupied))
@@ -129,5 +227,47 @@ func snakeFromProto(protoSnake *ProtoSnake) game.Snake {
 		Occupied:  occupied,
 		Direction: game.Direction(protoSnake.Direction),
 		Points:    uint16(protoSnake.Points),
+		Alive:     protoSnake.Alive,
+		Team:      uint8(protoSnake.Team),
+	}
+}
+
+// Convert Go Rules to Protobuf Rules
+func rulesToProto(rules game.Rules) *ProtoRules {
+	return &ProtoRules{
+		Name:          rules.Name,
+		GrowSize:      uint32(rules.GrowSize),
+		MapSwitch:     uint32(rules.MapSwitch),
+		Lives:         uint32(rules.Lives),
+		StartPerks:    uint32(rules.StartPerks),
+		PerkSpawnOdds: rules.PerkSpawnOdds,
+		DashLength:    uint32(rules.DashLength),
+		PerkTicks:     uint32(rules.PerkTicks),
+		TickMs:        rules.TickMillis,
+		HeadOn:        string(rules.HeadOn),
+		Mode:          string(rules.Mode),
+		ShrinkTicks:   rules.ShrinkTicks,
+		Teams:         uint32(rules.Teams),
+		FriendlyFire:  rules.FriendlyFire,
+	}
+}
+
+// Convert Protobuf Rules to Go Rules
+func rulesFromProto(protoRules *ProtoRules) game.Rules {
+	return game.Rules{
+		Name:          protoRules.GetName(),
+		GrowSize:      uint8(protoRules.GetGrowSize()),
+		MapSwitch:     uint16(protoRules.GetMapSwitch()),
+		Lives:         uint8(protoRules.GetLives()),
+		StartPerks:    uint16(protoRules.GetStartPerks()),
+		PerkSpawnOdds: protoRules.GetPerkSpawnOdds(),
+		DashLength:    uint8(protoRules.GetDashLength()),
+		PerkTicks:     uint16(protoRules.GetPerkTicks()),
+		TickMillis:    protoRules.GetTickMs(),
+		HeadOn:        game.HeadOnRule(protoRules.GetHeadOn()),
+		Mode:          game.GameMode(protoRules.GetMode()),
+		ShrinkTicks:   protoRules.GetShrinkTicks(),
+		Teams:         uint8(protoRules.GetTeams()),
+		FriendlyFire:  protoRules.GetFriendlyFire(),
 	}
 }
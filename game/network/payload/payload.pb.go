@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.14.0
+// 	protoc-gen-go v1.36.1
+// 	protoc        (unknown)
 // source: game/network/payload/payload.proto
 
 package payload
@@ -79,6 +79,10 @@ const (
 	ProtoPerkType_PROTO_PERK_TYPE_UNSPECIFIED ProtoPerkType = 0
 	ProtoPerkType_PROTO_PERK_TYPE_WALK_WALL   ProtoPerkType = 1
 	ProtoPerkType_PROTO_PERK_TYPE_DASH        ProtoPerkType = 2
+	ProtoPerkType_PROTO_PERK_TYPE_SHIELD      ProtoPerkType = 3
+	ProtoPerkType_PROTO_PERK_TYPE_GHOST       ProtoPerkType = 4
+	ProtoPerkType_PROTO_PERK_TYPE_MAGNET      ProtoPerkType = 5
+	ProtoPerkType_PROTO_PERK_TYPE_REVERSE     ProtoPerkType = 6
 )
 
 // Enum value maps for ProtoPerkType.
@@ -87,11 +91,19 @@ var (
 		0: "PROTO_PERK_TYPE_UNSPECIFIED",
 		1: "PROTO_PERK_TYPE_WALK_WALL",
 		2: "PROTO_PERK_TYPE_DASH",
+		3: "PROTO_PERK_TYPE_SHIELD",
+		4: "PROTO_PERK_TYPE_GHOST",
+		5: "PROTO_PERK_TYPE_MAGNET",
+		6: "PROTO_PERK_TYPE_REVERSE",
 	}
 	ProtoPerkType_value = map[string]int32{
 		"PROTO_PERK_TYPE_UNSPECIFIED": 0,
 		"PROTO_PERK_TYPE_WALK_WALL":   1,
 		"PROTO_PERK_TYPE_DASH":        2,
+		"PROTO_PERK_TYPE_SHIELD":      3,
+		"PROTO_PERK_TYPE_GHOST":       4,
+		"PROTO_PERK_TYPE_MAGNET":      5,
+		"PROTO_PERK_TYPE_REVERSE":     6,
 	}
 )
 
@@ -180,6 +192,10 @@ const (
 	ProtoCandyType_PROTO_CANDY_TYPE_GROW      ProtoCandyType = 0
 	ProtoCandyType_PROTO_CANDY_TYPE_WALK_WALL ProtoCandyType = 1
 	ProtoCandyType_PROTO_CANDY_TYPE_DASH      ProtoCandyType = 2
+	ProtoCandyType_PROTO_CANDY_TYPE_SHIELD    ProtoCandyType = 3
+	ProtoCandyType_PROTO_CANDY_TYPE_GHOST     ProtoCandyType = 4
+	ProtoCandyType_PROTO_CANDY_TYPE_MAGNET    ProtoCandyType = 5
+	ProtoCandyType_PROTO_CANDY_TYPE_REVERSE   ProtoCandyType = 6
 )
 
 // Enum value maps for ProtoCandyType.
@@ -188,11 +204,19 @@ var (
 		0: "PROTO_CANDY_TYPE_GROW",
 		1: "PROTO_CANDY_TYPE_WALK_WALL",
 		2: "PROTO_CANDY_TYPE_DASH",
+		3: "PROTO_CANDY_TYPE_SHIELD",
+		4: "PROTO_CANDY_TYPE_GHOST",
+		5: "PROTO_CANDY_TYPE_MAGNET",
+		6: "PROTO_CANDY_TYPE_REVERSE",
 	}
 	ProtoCandyType_value = map[string]int32{
 		"PROTO_CANDY_TYPE_GROW":      0,
 		"PROTO_CANDY_TYPE_WALK_WALL": 1,
 		"PROTO_CANDY_TYPE_DASH":      2,
+		"PROTO_CANDY_TYPE_SHIELD":    3,
+		"PROTO_CANDY_TYPE_GHOST":     4,
+		"PROTO_CANDY_TYPE_MAGNET":    5,
+		"PROTO_CANDY_TYPE_REVERSE":   6,
 	}
 )
 
@@ -223,23 +247,124 @@ func (ProtoCandyType) EnumDescriptor() ([]byte, []int) {
 	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{3}
 }
 
+type ProtoEventType int32
+
+const (
+	ProtoEventType_PROTO_EVENT_TYPE_PLAYER_DIED   ProtoEventType = 0
+	ProtoEventType_PROTO_EVENT_TYPE_CANDY_EATEN   ProtoEventType = 1
+	ProtoEventType_PROTO_EVENT_TYPE_PERK_USED     ProtoEventType = 2
+	ProtoEventType_PROTO_EVENT_TYPE_LEVEL_CHANGED ProtoEventType = 3
+)
+
+// Enum value maps for ProtoEventType.
+var (
+	ProtoEventType_name = map[int32]string{
+		0: "PROTO_EVENT_TYPE_PLAYER_DIED",
+		1: "PROTO_EVENT_TYPE_CANDY_EATEN",
+		2: "PROTO_EVENT_TYPE_PERK_USED",
+		3: "PROTO_EVENT_TYPE_LEVEL_CHANGED",
+	}
+	ProtoEventType_value = map[string]int32{
+		"PROTO_EVENT_TYPE_PLAYER_DIED":   0,
+		"PROTO_EVENT_TYPE_CANDY_EATEN":   1,
+		"PROTO_EVENT_TYPE_PERK_USED":     2,
+		"PROTO_EVENT_TYPE_LEVEL_CHANGED": 3,
+	}
+)
+
+func (x ProtoEventType) Enum() *ProtoEventType {
+	p := new(ProtoEventType)
+	*p = x
+	return p
+}
+
+func (x ProtoEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProtoEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_network_payload_payload_proto_enumTypes[4].Descriptor()
+}
+
+func (ProtoEventType) Type() protoreflect.EnumType {
+	return &file_game_network_payload_payload_proto_enumTypes[4]
+}
+
+func (x ProtoEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProtoEventType.Descriptor instead.
+func (ProtoEventType) EnumDescriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{4}
+}
+
+type ProtoDeathCause int32
+
+const (
+	ProtoDeathCause_PROTO_DEATH_CAUSE_WALL     ProtoDeathCause = 0
+	ProtoDeathCause_PROTO_DEATH_CAUSE_SELF     ProtoDeathCause = 1
+	ProtoDeathCause_PROTO_DEATH_CAUSE_OPPONENT ProtoDeathCause = 2
+	ProtoDeathCause_PROTO_DEATH_CAUSE_HEAD_ON  ProtoDeathCause = 3
+)
+
+// Enum value maps for ProtoDeathCause.
+var (
+	ProtoDeathCause_name = map[int32]string{
+		0: "PROTO_DEATH_CAUSE_WALL",
+		1: "PROTO_DEATH_CAUSE_SELF",
+		2: "PROTO_DEATH_CAUSE_OPPONENT",
+		3: "PROTO_DEATH_CAUSE_HEAD_ON",
+	}
+	ProtoDeathCause_value = map[string]int32{
+		"PROTO_DEATH_CAUSE_WALL":     0,
+		"PROTO_DEATH_CAUSE_SELF":     1,
+		"PROTO_DEATH_CAUSE_OPPONENT": 2,
+		"PROTO_DEATH_CAUSE_HEAD_ON":  3,
+	}
+)
+
+func (x ProtoDeathCause) Enum() *ProtoDeathCause {
+	p := new(ProtoDeathCause)
+	*p = x
+	return p
+}
+
+func (x ProtoDeathCause) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProtoDeathCause) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_network_payload_payload_proto_enumTypes[5].Descriptor()
+}
+
+func (ProtoDeathCause) Type() protoreflect.EnumType {
+	return &file_game_network_payload_payload_proto_enumTypes[5]
+}
+
+func (x ProtoDeathCause) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProtoDeathCause.Descriptor instead.
+func (ProtoDeathCause) EnumDescriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{5}
+}
+
 // Messages
 type ProtoPosition struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Y             uint32                 `protobuf:"varint,1,opt,name=y,proto3" json:"y,omitempty"`
+	X             uint32                 `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Y uint32 `protobuf:"varint,1,opt,name=y,proto3" json:"y,omitempty"`
-	X uint32 `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProtoPosition) Reset() {
 	*x = ProtoPosition{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_game_network_payload_payload_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_game_network_payload_payload_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ProtoPosition) String() string {
@@ -250,7 +375,7 @@ func (*ProtoPosition) ProtoMessage() {}
 
 func (x *ProtoPosition) ProtoReflect() protoreflect.Message {
 	mi := &file_game_network_payload_payload_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -280,21 +405,18 @@ func (x *ProtoPosition) GetX() uint32 {
 }
 
 type ProtoCandy struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          ProtoCandyType         `protobuf:"varint,1,opt,name=type,proto3,enum=payload.ProtoCandyType" json:"type,omitempty"`
+	Position      *ProtoPosition         `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Type     ProtoCandyType `protobuf:"varint,1,opt,name=type,proto3,enum=payload.ProtoCandyType" json:"type,omitempty"`
-	Position *ProtoPosition `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProtoCandy) Reset() {
 	*x = ProtoCandy{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_game_network_payload_payload_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_game_network_payload_payload_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ProtoCandy) String() string {
@@ -305,7 +427,7 @@ func (*ProtoCandy) ProtoMessage() {}
 
 func (x *ProtoCandy) ProtoReflect() protoreflect.Message {
 	mi := &file_game_network_payload_payload_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -335,21 +457,19 @@ func (x *ProtoCandy) GetPosition() *ProtoPosition {
 }
 
 type ProtoPerk struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          ProtoPerkType          `protobuf:"varint,1,opt,name=type,proto3,enum=payload.ProtoPerkType" json:"type,omitempty"`
+	Usages        uint32                 `protobuf:"varint,2,opt,name=usages,proto3" json:"usages,omitempty"`
+	Ticks         uint32                 `protobuf:"varint,3,opt,name=ticks,proto3" json:"ticks,omitempty"` // Remaining ticks of a timed perk.
 	unknownFields protoimpl.UnknownFields
-
-	Type   ProtoPerkType `protobuf:"varint,1,opt,name=type,proto3,enum=payload.ProtoPerkType" json:"type,omitempty"`
-	Usages uint32        `protobuf:"varint,2,opt,name=usages,proto3" json:"usages,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProtoPerk) Reset() {
 	*x = ProtoPerk{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_game_network_payload_payload_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_game_network_payload_payload_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ProtoPerk) String() string {
@@ -360,7 +480,7 @@ func (*ProtoPerk) ProtoMessage() {}
 
 func (x *ProtoPerk) ProtoReflect() protoreflect.Message {
 	mi := &file_game_network_payload_payload_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -389,25 +509,36 @@ func (x *ProtoPerk) GetUsages() uint32 {
 	return 0
 }
 
-type ProtoSnake struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *ProtoPerk) GetTicks() uint32 {
+	if x != nil {
+		return x.Ticks
+	}
+	return 0
+}
 
-	Perks     map[int32]*ProtoPerk `protobuf:"bytes,1,rep,name=perks,proto3" json:"perks,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Perks map keyed by ProtoPerkType.
-	Lives     uint32               `protobuf:"varint,2,opt,name=lives,proto3" json:"lives,omitempty"`
-	Occupied  []*ProtoPosition     `protobuf:"bytes,3,rep,name=occupied,proto3" json:"occupied,omitempty"`
-	Direction ProtoDirection       `protobuf:"varint,4,opt,name=direction,proto3,enum=payload.ProtoDirection" json:"direction,omitempty"`
-	Points    uint32               `protobuf:"varint,5,opt,name=points,proto3" json:"points,omitempty"` // uint32 grows = 6;
+type ProtoSnake struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Perks     map[int32]*ProtoPerk   `protobuf:"bytes,1,rep,name=perks,proto3" json:"perks,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Perks map keyed by ProtoPerkType.
+	Lives     uint32                 `protobuf:"varint,2,opt,name=lives,proto3" json:"lives,omitempty"`
+	Occupied  []*ProtoPosition       `protobuf:"bytes,3,rep,name=occupied,proto3" json:"occupied,omitempty"`
+	Direction ProtoDirection         `protobuf:"varint,4,opt,name=direction,proto3,enum=payload.ProtoDirection" json:"direction,omitempty"`
+	Points    uint32                 `protobuf:"varint,5,opt,name=points,proto3" json:"points,omitempty"`
+	// uint32 grows = 6;
+	Alive bool   `protobuf:"varint,7,opt,name=alive,proto3" json:"alive,omitempty"`
+	Team  uint32 `protobuf:"varint,8,opt,name=team,proto3" json:"team,omitempty"` // 0 if the snake plays for itself.
+	// Body length of a delta snake, occupied then only holds the positions
+	// added to the body of the baseline snake.
+	Length         uint32 `protobuf:"varint,9,opt,name=length,proto3" json:"length,omitempty"`
+	PerksUnchanged bool   `protobuf:"varint,10,opt,name=perks_unchanged,json=perksUnchanged,proto3" json:"perks_unchanged,omitempty"` // A delta snake has the perks of the baseline snake.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ProtoSnake) Reset() {
 	*x = ProtoSnake{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_game_network_payload_payload_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_game_network_payload_payload_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ProtoSnake) String() string {
@@ -418,7 +549,7 @@ func (*ProtoSnake) ProtoMessage() {}
 
 func (x *ProtoSnake) ProtoReflect() protoreflect.Message {
 	mi := &file_game_network_payload_payload_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -468,25 +599,221 @@ func (x *ProtoSnake) GetPoints() uint32 {
 	return 0
 }
 
-type ProtoPayload struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+func (x *ProtoSnake) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *ProtoSnake) GetTeam() uint32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+func (x *ProtoSnake) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *ProtoSnake) GetPerksUnchanged() bool {
+	if x != nil {
+		return x.PerksUnchanged
+	}
+	return false
+}
+
+type ProtoRules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	GrowSize      uint32                 `protobuf:"varint,2,opt,name=grow_size,json=growSize,proto3" json:"grow_size,omitempty"`
+	MapSwitch     uint32                 `protobuf:"varint,3,opt,name=map_switch,json=mapSwitch,proto3" json:"map_switch,omitempty"`
+	Lives         uint32                 `protobuf:"varint,4,opt,name=lives,proto3" json:"lives,omitempty"`
+	StartPerks    uint32                 `protobuf:"varint,5,opt,name=start_perks,json=startPerks,proto3" json:"start_perks,omitempty"`
+	PerkSpawnOdds uint32                 `protobuf:"varint,6,opt,name=perk_spawn_odds,json=perkSpawnOdds,proto3" json:"perk_spawn_odds,omitempty"`
+	DashLength    uint32                 `protobuf:"varint,7,opt,name=dash_length,json=dashLength,proto3" json:"dash_length,omitempty"`
+	TickMs        uint32                 `protobuf:"varint,8,opt,name=tick_ms,json=tickMs,proto3" json:"tick_ms,omitempty"`
+	HeadOn        string                 `protobuf:"bytes,9,opt,name=head_on,json=headOn,proto3" json:"head_on,omitempty"`
+	Mode          string                 `protobuf:"bytes,10,opt,name=mode,proto3" json:"mode,omitempty"`
+	ShrinkTicks   uint32                 `protobuf:"varint,11,opt,name=shrink_ticks,json=shrinkTicks,proto3" json:"shrink_ticks,omitempty"`
+	Teams         uint32                 `protobuf:"varint,12,opt,name=teams,proto3" json:"teams,omitempty"`
+	FriendlyFire  bool                   `protobuf:"varint,13,opt,name=friendly_fire,json=friendlyFire,proto3" json:"friendly_fire,omitempty"`
+	PerkTicks     uint32                 `protobuf:"varint,14,opt,name=perk_ticks,json=perkTicks,proto3" json:"perk_ticks,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	MapLevel  uint32         `protobuf:"varint,1,opt,name=map_level,json=mapLevel,proto3" json:"map_level,omitempty"`
-	GameState ProtoGameState `protobuf:"varint,2,opt,name=game_state,json=gameState,proto3,enum=payload.ProtoGameState" json:"game_state,omitempty"`
-	Candies   []*ProtoCandy  `protobuf:"bytes,3,rep,name=candies,proto3" json:"candies,omitempty"`
-	Player    *ProtoSnake    `protobuf:"bytes,4,opt,name=player,proto3" json:"player,omitempty"`
-	Opponents []*ProtoSnake  `protobuf:"bytes,5,rep,name=opponents,proto3" json:"opponents,omitempty"`
+func (x *ProtoRules) Reset() {
+	*x = ProtoRules{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProtoPayload) Reset() {
-	*x = ProtoPayload{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_game_network_payload_payload_proto_msgTypes[4]
+func (x *ProtoRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoRules) ProtoMessage() {}
+
+func (x *ProtoRules) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[4]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoRules.ProtoReflect.Descriptor instead.
+func (*ProtoRules) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProtoRules) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProtoRules) GetGrowSize() uint32 {
+	if x != nil {
+		return x.GrowSize
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetMapSwitch() uint32 {
+	if x != nil {
+		return x.MapSwitch
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetLives() uint32 {
+	if x != nil {
+		return x.Lives
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetStartPerks() uint32 {
+	if x != nil {
+		return x.StartPerks
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetPerkSpawnOdds() uint32 {
+	if x != nil {
+		return x.PerkSpawnOdds
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetDashLength() uint32 {
+	if x != nil {
+		return x.DashLength
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetTickMs() uint32 {
+	if x != nil {
+		return x.TickMs
 	}
+	return 0
+}
+
+func (x *ProtoRules) GetHeadOn() string {
+	if x != nil {
+		return x.HeadOn
+	}
+	return ""
+}
+
+func (x *ProtoRules) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ProtoRules) GetShrinkTicks() uint32 {
+	if x != nil {
+		return x.ShrinkTicks
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetTeams() uint32 {
+	if x != nil {
+		return x.Teams
+	}
+	return 0
+}
+
+func (x *ProtoRules) GetFriendlyFire() bool {
+	if x != nil {
+		return x.FriendlyFire
+	}
+	return false
+}
+
+func (x *ProtoRules) GetPerkTicks() uint32 {
+	if x != nil {
+		return x.PerkTicks
+	}
+	return 0
+}
+
+type ProtoPayload struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	MapLevel     uint32                 `protobuf:"varint,1,opt,name=map_level,json=mapLevel,proto3" json:"map_level,omitempty"`
+	GameState    ProtoGameState         `protobuf:"varint,2,opt,name=game_state,json=gameState,proto3,enum=payload.ProtoGameState" json:"game_state,omitempty"`
+	Candies      []*ProtoCandy          `protobuf:"bytes,3,rep,name=candies,proto3" json:"candies,omitempty"`
+	Player       *ProtoSnake            `protobuf:"bytes,4,opt,name=player,proto3" json:"player,omitempty"`
+	Opponents    []*ProtoSnake          `protobuf:"bytes,5,rep,name=opponents,proto3" json:"opponents,omitempty"`
+	Map          []byte                 `protobuf:"bytes,6,opt,name=map,proto3" json:"map,omitempty"`           // Text encoded map, only set for custom maps.
+	Rules        *ProtoRules            `protobuf:"bytes,7,opt,name=rules,proto3" json:"rules,omitempty"`       // Not set in a delta if the rules of the baseline are unchanged.
+	Shrink       uint32                 `protobuf:"varint,8,opt,name=shrink,proto3" json:"shrink,omitempty"`    // Wall rings the battle royale map has shrunk by.
+	Profiles     []*ProtoProfile        `protobuf:"bytes,9,rep,name=profiles,proto3" json:"profiles,omitempty"` // Profile of the player, followed by the opponents.
+	Lobby        *ProtoLobby            `protobuf:"bytes,10,opt,name=lobby,proto3" json:"lobby,omitempty"`      // Only set while the server waits in the lobby.
+	Spectator    bool                   `protobuf:"varint,11,opt,name=spectator,proto3" json:"spectator,omitempty"`
+	Snakes       []*ProtoSnake          `protobuf:"bytes,12,rep,name=snakes,proto3" json:"snakes,omitempty"` // Every snake in slot order, only sent to spectators.
+	Sequence     uint32                 `protobuf:"varint,13,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Baseline     uint32                 `protobuf:"varint,14,opt,name=baseline,proto3" json:"baseline,omitempty"`                             // Sequence the snakes are a delta against, 0 for a keyframe.
+	InputAck     uint32                 `protobuf:"varint,15,opt,name=input_ack,json=inputAck,proto3" json:"input_ack,omitempty"`             // Sequence of the last input the server got from the client.
+	Tick         uint32                 `protobuf:"varint,16,opt,name=tick,proto3" json:"tick,omitempty"`                                     // Ticks played in the current round.
+	InputApplied uint32                 `protobuf:"varint,17,opt,name=input_applied,json=inputApplied,proto3" json:"input_applied,omitempty"` // Sequence of the last input of the client the state includes.
+	ServerTick   uint32                 `protobuf:"varint,18,opt,name=server_tick,json=serverTick,proto3" json:"server_tick,omitempty"`       // Steps the server has played, unlike tick it never starts over.
+	ServerTime   int64                  `protobuf:"varint,19,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`       // Unix time in microseconds the state was sent at.
+	EchoTime     uint64                 `protobuf:"varint,20,opt,name=echo_time,json=echoTime,proto3" json:"echo_time,omitempty"`             // Client time of the last ack of the client.
+	EchoDelay    uint32                 `protobuf:"varint,21,opt,name=echo_delay,json=echoDelay,proto3" json:"echo_delay,omitempty"`          // Microseconds between the last ack and sending the state.
+	Events       []*ProtoEvent          `protobuf:"bytes,22,rep,name=events,proto3" json:"events,omitempty"`                                  // Events the client has not acknowledged yet.
+	// Deltas leave out what did not change since the baseline. Their candies
+	// only hold the added candies, removed_candies the positions of the
+	// candies that are gone.
+	MapUnchanged      bool             `protobuf:"varint,23,opt,name=map_unchanged,json=mapUnchanged,proto3" json:"map_unchanged,omitempty"`
+	ProfilesUnchanged bool             `protobuf:"varint,24,opt,name=profiles_unchanged,json=profilesUnchanged,proto3" json:"profiles_unchanged,omitempty"`
+	RemovedCandies    []*ProtoPosition `protobuf:"bytes,25,rep,name=removed_candies,json=removedCandies,proto3" json:"removed_candies,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ProtoPayload) Reset() {
+	*x = ProtoPayload{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ProtoPayload) String() string {
@@ -496,8 +823,8 @@ func (x *ProtoPayload) String() string {
 func (*ProtoPayload) ProtoMessage() {}
 
 func (x *ProtoPayload) ProtoReflect() protoreflect.Message {
-	mi := &file_game_network_payload_payload_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_game_network_payload_payload_proto_msgTypes[5]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -509,7 +836,7 @@ func (x *ProtoPayload) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoPayload.ProtoReflect.Descriptor instead.
 func (*ProtoPayload) Descriptor() ([]byte, []int) {
-	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{4}
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ProtoPayload) GetMapLevel() uint32 {
@@ -547,213 +874,1167 @@ func (x *ProtoPayload) GetOpponents() []*ProtoSnake {
 	return nil
 }
 
-var File_game_network_payload_payload_proto protoreflect.FileDescriptor
-
-var file_game_network_payload_payload_proto_rawDesc = []byte{
-	0x0a, 0x22, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70,
-	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x2b, 0x0a,
-	0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0c,
-	0x0a, 0x01, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01,
-	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x78, 0x22, 0x6d, 0x0a, 0x0a, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e, 0x64, 0x79, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
-	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e, 0x64, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
-	0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4f, 0x0a, 0x09, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x06, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0xa9, 0x02, 0x0a, 0x0a, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e, 0x61, 0x6b, 0x65, 0x12, 0x34, 0x0a, 0x05, 0x70, 0x65, 0x72,
-	0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f,
-	0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e, 0x61, 0x6b, 0x65, 0x2e, 0x50, 0x65,
-	0x72, 0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x70, 0x65, 0x72, 0x6b, 0x73, 0x12,
-	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
-	0x6c, 0x69, 0x76, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x69, 0x65,
-	0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
-	0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x08, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x69, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x09, 0x64, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70,
-	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x69, 0x72, 0x65,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x1a, 0x4c, 0x0a, 0x0a, 0x50, 0x65, 0x72, 0x6b,
-	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
-	0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xf2, 0x01, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x70, 0x5f, 0x6c,
-	0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x61, 0x70, 0x4c,
-	0x65, 0x76, 0x65, 0x6c, 0x12, 0x36, 0x0a, 0x0a, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x74, 0x61,
-	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f,
-	0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x52, 0x09, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2d, 0x0a, 0x07,
-	0x63, 0x61, 0x6e, 0x64, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e,
-	0x64, 0x79, 0x52, 0x07, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x06, 0x70,
-	0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x61,
-	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e, 0x61, 0x6b, 0x65,
-	0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x09, 0x6f, 0x70, 0x70, 0x6f,
-	0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x61,
-	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e, 0x61, 0x6b, 0x65,
-	0x52, 0x09, 0x6f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x2a, 0x94, 0x01, 0x0a, 0x0e,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b,
-	0x0a, 0x17, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41,
-	0x54, 0x45, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x50,
-	0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
-	0x4f, 0x4e, 0x47, 0x4f, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f, 0x50, 0x52, 0x4f,
-	0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x52, 0x4f,
-	0x55, 0x4e, 0x44, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x02, 0x12, 0x22,
-	0x0a, 0x1e, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41,
-	0x54, 0x45, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44,
-	0x10, 0x03, 0x2a, 0x69, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x1b, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x52,
-	0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
-	0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45,
-	0x52, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x57, 0x41, 0x4c, 0x4b, 0x5f, 0x57, 0x41, 0x4c,
-	0x4c, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x52,
-	0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x41, 0x53, 0x48, 0x10, 0x02, 0x2a, 0x7a, 0x0a,
-	0x0e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49,
-	0x4f, 0x4e, 0x5f, 0x45, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x52, 0x4f,
-	0x54, 0x4f, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x52,
-	0x54, 0x48, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x49,
-	0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x4f, 0x55, 0x54, 0x48, 0x10, 0x02, 0x12,
-	0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49,
-	0x4f, 0x4e, 0x5f, 0x57, 0x45, 0x53, 0x54, 0x10, 0x03, 0x2a, 0x66, 0x0a, 0x0e, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x43, 0x61, 0x6e, 0x64, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x15, 0x50,
-	0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
-	0x47, 0x52, 0x4f, 0x57, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f,
-	0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x57, 0x41, 0x4c, 0x4b, 0x5f,
-	0x57, 0x41, 0x4c, 0x4c, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f,
-	0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x41, 0x53, 0x48, 0x10,
-	0x02, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ProtoPayload) GetMap() []byte {
+	if x != nil {
+		return x.Map
+	}
+	return nil
 }
 
-var (
-	file_game_network_payload_payload_proto_rawDescOnce sync.Once
-	file_game_network_payload_payload_proto_rawDescData = file_game_network_payload_payload_proto_rawDesc
-)
+func (x *ProtoPayload) GetRules() *ProtoRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
 
-func file_game_network_payload_payload_proto_rawDescGZIP() []byte {
-	file_game_network_payload_payload_proto_rawDescOnce.Do(func() {
-		file_game_network_payload_payload_proto_rawDescData = protoimpl.X.CompressGZIP(file_game_network_payload_payload_proto_rawDescData)
-	})
-	return file_game_network_payload_payload_proto_rawDescData
+func (x *ProtoPayload) GetShrink() uint32 {
+	if x != nil {
+		return x.Shrink
+	}
+	return 0
 }
 
-var file_game_network_payload_payload_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_game_network_payload_payload_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_game_network_payload_payload_proto_goTypes = []interface{}{
-	(ProtoGameState)(0),   // 0: payload.ProtoGameState
-	(ProtoPerkType)(0),    // 1: payload.ProtoPerkType
-	(ProtoDirection)(0),   // 2: payload.ProtoDirection
-	(ProtoCandyType)(0),   // 3: payload.ProtoCandyType
-	(*ProtoPosition)(nil), // 4: payload.ProtoPosition
-	(*ProtoCandy)(nil),    // 5: payload.ProtoCandy
-	(*ProtoPerk)(nil),     // 6: payload.ProtoPerk
-	(*ProtoSnake)(nil),    // 7: payload.ProtoSnake
-	(*ProtoPayload)(nil),  // 8: payload.ProtoPayload
-	nil,                   // 9: payload.ProtoSnake.PerksEntry
+func (x *ProtoPayload) GetProfiles() []*ProtoProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
 }
-var file_game_network_payload_payload_proto_depIdxs = []int32{
-	3,  // 0: payload.ProtoCandy.type:type_name -> payload.ProtoCandyType
-	4,  // 1: payload.ProtoCandy.position:type_name -> payload.ProtoPosition
-	1,  // 2: payload.ProtoPerk.type:type_name -> payload.ProtoPerkType
-	9,  // 3: payload.ProtoSnake.perks:type_name -> payload.ProtoSnake.PerksEntry
-	4,  // 4: payload.ProtoSnake.occupied:type_name -> payload.ProtoPosition
-	2,  // 5: payload.ProtoSnake.direction:type_name -> payload.ProtoDirection
-	0,  // 6: payload.ProtoPayload.game_state:type_name -> payload.ProtoGameState
-	5,  // 7: payload.ProtoPayload.candies:type_name -> payload.ProtoCandy
-	7,  // 8: payload.ProtoPayload.player:type_name -> payload.ProtoSnake
-	7,  // 9: payload.ProtoPayload.opponents:type_name -> payload.ProtoSnake
-	6,  // 10: payload.ProtoSnake.PerksEntry.value:type_name -> payload.ProtoPerk
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+
+func (x *ProtoPayload) GetLobby() *ProtoLobby {
+	if x != nil {
+		return x.Lobby
+	}
+	return nil
 }
 
-func init() { file_game_network_payload_payload_proto_init() }
-func file_game_network_payload_payload_proto_init() {
-	if File_game_network_payload_payload_proto != nil {
-		return
+func (x *ProtoPayload) GetSpectator() bool {
+	if x != nil {
+		return x.Spectator
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_game_network_payload_payload_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPosition); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_game_network_payload_payload_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoCandy); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return false
+}
+
+func (x *ProtoPayload) GetSnakes() []*ProtoSnake {
+	if x != nil {
+		return x.Snakes
+	}
+	return nil
+}
+
+func (x *ProtoPayload) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetBaseline() uint32 {
+	if x != nil {
+		return x.Baseline
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetInputAck() uint32 {
+	if x != nil {
+		return x.InputAck
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetTick() uint32 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetInputApplied() uint32 {
+	if x != nil {
+		return x.InputApplied
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetServerTick() uint32 {
+	if x != nil {
+		return x.ServerTick
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetServerTime() int64 {
+	if x != nil {
+		return x.ServerTime
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetEchoTime() uint64 {
+	if x != nil {
+		return x.EchoTime
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetEchoDelay() uint32 {
+	if x != nil {
+		return x.EchoDelay
+	}
+	return 0
+}
+
+func (x *ProtoPayload) GetEvents() []*ProtoEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ProtoPayload) GetMapUnchanged() bool {
+	if x != nil {
+		return x.MapUnchanged
+	}
+	return false
+}
+
+func (x *ProtoPayload) GetProfilesUnchanged() bool {
+	if x != nil {
+		return x.ProfilesUnchanged
+	}
+	return false
+}
+
+func (x *ProtoPayload) GetRemovedCandies() []*ProtoPosition {
+	if x != nil {
+		return x.RemovedCandies
+	}
+	return nil
+}
+
+// ProtoEvent only sets the fields of its type.
+type ProtoEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint32                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Type          ProtoEventType         `protobuf:"varint,2,opt,name=type,proto3,enum=payload.ProtoEventType" json:"type,omitempty"`
+	Player        uint32                 `protobuf:"varint,3,opt,name=player,proto3" json:"player,omitempty"` // Index in the order of the profiles.
+	Position      *ProtoPosition         `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	Candy         ProtoCandyType         `protobuf:"varint,5,opt,name=candy,proto3,enum=payload.ProtoCandyType" json:"candy,omitempty"`
+	Perk          ProtoPerkType          `protobuf:"varint,6,opt,name=perk,proto3,enum=payload.ProtoPerkType" json:"perk,omitempty"`
+	Cause         ProtoDeathCause        `protobuf:"varint,7,opt,name=cause,proto3,enum=payload.ProtoDeathCause" json:"cause,omitempty"`
+	Opponent      int32                  `protobuf:"varint,8,opt,name=opponent,proto3" json:"opponent,omitempty"` // Index like player, -1 if no snake caused the death.
+	Level         uint32                 `protobuf:"varint,9,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtoEvent) Reset() {
+	*x = ProtoEvent{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoEvent) ProtoMessage() {}
+
+func (x *ProtoEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_game_network_payload_payload_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPerk); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoEvent.ProtoReflect.Descriptor instead.
+func (*ProtoEvent) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProtoEvent) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *ProtoEvent) GetType() ProtoEventType {
+	if x != nil {
+		return x.Type
+	}
+	return ProtoEventType_PROTO_EVENT_TYPE_PLAYER_DIED
+}
+
+func (x *ProtoEvent) GetPlayer() uint32 {
+	if x != nil {
+		return x.Player
+	}
+	return 0
+}
+
+func (x *ProtoEvent) GetPosition() *ProtoPosition {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *ProtoEvent) GetCandy() ProtoCandyType {
+	if x != nil {
+		return x.Candy
+	}
+	return ProtoCandyType_PROTO_CANDY_TYPE_GROW
+}
+
+func (x *ProtoEvent) GetPerk() ProtoPerkType {
+	if x != nil {
+		return x.Perk
+	}
+	return ProtoPerkType_PROTO_PERK_TYPE_UNSPECIFIED
+}
+
+func (x *ProtoEvent) GetCause() ProtoDeathCause {
+	if x != nil {
+		return x.Cause
+	}
+	return ProtoDeathCause_PROTO_DEATH_CAUSE_WALL
+}
+
+func (x *ProtoEvent) GetOpponent() int32 {
+	if x != nil {
+		return x.Opponent
+	}
+	return 0
+}
+
+func (x *ProtoEvent) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+// A key press of a client. The client resends its inputs until the server
+// acknowledges them.
+type ProtoInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint32                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Tick          uint32                 `protobuf:"varint,2,opt,name=tick,proto3" json:"tick,omitempty"` // Tick the client saw when the key was pressed.
+	Key           uint32                 `protobuf:"varint,3,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtoInput) Reset() {
+	*x = ProtoInput{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoInput) ProtoMessage() {}
+
+func (x *ProtoInput) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_game_network_payload_payload_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoSnake); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoInput.ProtoReflect.Descriptor instead.
+func (*ProtoInput) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ProtoInput) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *ProtoInput) GetTick() uint32 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *ProtoInput) GetKey() uint32 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+type ProtoInputs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inputs        []*ProtoInput          `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtoInputs) Reset() {
+	*x = ProtoInputs{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoInputs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoInputs) ProtoMessage() {}
+
+func (x *ProtoInputs) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_game_network_payload_payload_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPayload); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoInputs.ProtoReflect.Descriptor instead.
+func (*ProtoInputs) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProtoInputs) GetInputs() []*ProtoInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type ProtoLobbySlot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       *ProtoProfile          `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Connected     bool                   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
+	Ready         bool                   `protobuf:"varint,3,opt,name=ready,proto3" json:"ready,omitempty"`
+	Bot           bool                   `protobuf:"varint,4,opt,name=bot,proto3" json:"bot,omitempty"`
+	Team          uint32                 `protobuf:"varint,5,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtoLobbySlot) Reset() {
+	*x = ProtoLobbySlot{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoLobbySlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoLobbySlot) ProtoMessage() {}
+
+func (x *ProtoLobbySlot) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoLobbySlot.ProtoReflect.Descriptor instead.
+func (*ProtoLobbySlot) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ProtoLobbySlot) GetProfile() *ProtoProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *ProtoLobbySlot) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *ProtoLobbySlot) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *ProtoLobbySlot) GetBot() bool {
+	if x != nil {
+		return x.Bot
+	}
+	return false
+}
+
+func (x *ProtoLobbySlot) GetTeam() uint32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+type ProtoLobby struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slots         []*ProtoLobbySlot      `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"`
+	Own           int32                  `protobuf:"varint,2,opt,name=own,proto3" json:"own,omitempty"` // Slot of the receiving client, -1 for spectators.
+	MapName       string                 `protobuf:"bytes,3,opt,name=map_name,json=mapName,proto3" json:"map_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtoLobby) Reset() {
+	*x = ProtoLobby{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoLobby) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoLobby) ProtoMessage() {}
+
+func (x *ProtoLobby) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoLobby.ProtoReflect.Descriptor instead.
+func (*ProtoLobby) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProtoLobby) GetSlots() []*ProtoLobbySlot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+func (x *ProtoLobby) GetOwn() int32 {
+	if x != nil {
+		return x.Own
+	}
+	return 0
+}
+
+func (x *ProtoLobby) GetMapName() string {
+	if x != nil {
+		return x.MapName
+	}
+	return ""
+}
+
+type ProtoProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Color         uint32                 `protobuf:"varint,2,opt,name=color,proto3" json:"color,omitempty"` // Preferred snake color as 0xRRGGBB, 0 if the player has none.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtoProfile) Reset() {
+	*x = ProtoProfile{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoProfile) ProtoMessage() {}
+
+func (x *ProtoProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoProfile.ProtoReflect.Descriptor instead.
+func (*ProtoProfile) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ProtoProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProtoProfile) GetColor() uint32 {
+	if x != nil {
+		return x.Color
+	}
+	return 0
+}
+
+// Sent by the client to join a game, or to reconnect with a session token.
+type ProtoHello struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProtocolVersion uint32                 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Profile         *ProtoProfile          `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	SessionToken    string                 `protobuf:"bytes,3,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	Spectator       bool                   `protobuf:"varint,4,opt,name=spectator,proto3" json:"spectator,omitempty"` // Spectators watch without taking a player slot.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProtoHello) Reset() {
+	*x = ProtoHello{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoHello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoHello) ProtoMessage() {}
+
+func (x *ProtoHello) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoHello.ProtoReflect.Descriptor instead.
+func (*ProtoHello) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ProtoHello) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *ProtoHello) GetProfile() *ProtoProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *ProtoHello) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *ProtoHello) GetSpectator() bool {
+	if x != nil {
+		return x.Spectator
+	}
+	return false
+}
+
+type ProtoHelloReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProtocolVersion uint32                 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Accepted        bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Why the server rejected the hello.
+	SessionToken    string                 `protobuf:"bytes,4,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	PlayerIndex     uint32                 `protobuf:"varint,5,opt,name=player_index,json=playerIndex,proto3" json:"player_index,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProtoHelloReply) Reset() {
+	*x = ProtoHelloReply{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoHelloReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoHelloReply) ProtoMessage() {}
+
+func (x *ProtoHelloReply) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoHelloReply.ProtoReflect.Descriptor instead.
+func (*ProtoHelloReply) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProtoHelloReply) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *ProtoHelloReply) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *ProtoHelloReply) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProtoHelloReply) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *ProtoHelloReply) GetPlayerIndex() uint32 {
+	if x != nil {
+		return x.PlayerIndex
+	}
+	return 0
+}
+
+// Broadcast on the LAN by servers with an open lobby.
+type ProtoAnnouncement struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProtocolVersion uint32                 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Port            uint32                 `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"` // Game port, the host is the sender of the announcement.
+	MapName         string                 `protobuf:"bytes,4,opt,name=map_name,json=mapName,proto3" json:"map_name,omitempty"`
+	Rules           string                 `protobuf:"bytes,5,opt,name=rules,proto3" json:"rules,omitempty"`
+	Players         uint32                 `protobuf:"varint,6,opt,name=players,proto3" json:"players,omitempty"` // Taken player slots, bots included.
+	Capacity        uint32                 `protobuf:"varint,7,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Network         string                 `protobuf:"bytes,8,opt,name=network,proto3" json:"network,omitempty"` // Transport the clients connect with, "udp", "tcp" or "ws".
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProtoAnnouncement) Reset() {
+	*x = ProtoAnnouncement{}
+	mi := &file_game_network_payload_payload_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoAnnouncement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoAnnouncement) ProtoMessage() {}
+
+func (x *ProtoAnnouncement) ProtoReflect() protoreflect.Message {
+	mi := &file_game_network_payload_payload_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoAnnouncement.ProtoReflect.Descriptor instead.
+func (*ProtoAnnouncement) Descriptor() ([]byte, []int) {
+	return file_game_network_payload_payload_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProtoAnnouncement) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *ProtoAnnouncement) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProtoAnnouncement) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ProtoAnnouncement) GetMapName() string {
+	if x != nil {
+		return x.MapName
+	}
+	return ""
+}
+
+func (x *ProtoAnnouncement) GetRules() string {
+	if x != nil {
+		return x.Rules
+	}
+	return ""
+}
+
+func (x *ProtoAnnouncement) GetPlayers() uint32 {
+	if x != nil {
+		return x.Players
+	}
+	return 0
+}
+
+func (x *ProtoAnnouncement) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *ProtoAnnouncement) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+var File_game_network_payload_payload_proto protoreflect.FileDescriptor
+
+var file_game_network_payload_payload_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x2b, 0x0a,
+	0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0c,
+	0x0a, 0x01, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x78, 0x22, 0x6d, 0x0a, 0x0a, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e, 0x64, 0x79, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e, 0x64, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x65, 0x0a, 0x09, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69,
+	0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x69, 0x63, 0x6b, 0x73,
+	0x22, 0x94, 0x03, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e, 0x61, 0x6b, 0x65, 0x12,
+	0x34, 0x0a, 0x05, 0x70, 0x65, 0x72, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e,
+	0x61, 0x6b, 0x65, 0x2e, 0x50, 0x65, 0x72, 0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05,
+	0x70, 0x65, 0x72, 0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x6f,
+	0x63, 0x63, 0x75, 0x70, 0x69, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x69, 0x65, 0x64, 0x12,
+	0x35, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61,
+	0x6c, 0x69, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x12, 0x27, 0x0a, 0x0f, 0x70, 0x65, 0x72, 0x6b, 0x73, 0x5f, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x70, 0x65, 0x72, 0x6b, 0x73,
+	0x55, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x1a, 0x4c, 0x0a, 0x0a, 0x50, 0x65, 0x72,
+	0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x9f, 0x03, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72,
+	0x6f, 0x77, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x67,
+	0x72, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x70, 0x5f, 0x73,
+	0x77, 0x69, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6d, 0x61, 0x70,
+	0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x65, 0x72, 0x6b, 0x73, 0x12, 0x26, 0x0a,
+	0x0f, 0x70, 0x65, 0x72, 0x6b, 0x5f, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x5f, 0x6f, 0x64, 0x64, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x70, 0x65, 0x72, 0x6b, 0x53, 0x70, 0x61, 0x77,
+	0x6e, 0x4f, 0x64, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x61, 0x73, 0x68, 0x5f, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x64, 0x61, 0x73, 0x68,
+	0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x69, 0x63, 0x6b, 0x5f, 0x6d,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x4d, 0x73, 0x12,
+	0x17, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x68, 0x72, 0x69, 0x6e, 0x6b, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x73, 0x68, 0x72, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x63, 0x6b, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x74, 0x65, 0x61, 0x6d, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x72, 0x69, 0x65, 0x6e, 0x64, 0x6c,
+	0x79, 0x5f, 0x66, 0x69, 0x72, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x72,
+	0x69, 0x65, 0x6e, 0x64, 0x6c, 0x79, 0x46, 0x69, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x65,
+	0x72, 0x6b, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x70, 0x65, 0x72, 0x6b, 0x54, 0x69, 0x63, 0x6b, 0x73, 0x22, 0xbe, 0x07, 0x0a, 0x0c, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61,
+	0x70, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d,
+	0x61, 0x70, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x36, 0x0a, 0x0a, 0x67, 0x61, 0x6d, 0x65, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x47, 0x61, 0x6d, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x2d, 0x0a, 0x07, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x43, 0x61, 0x6e, 0x64, 0x79, 0x52, 0x07, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x65, 0x73, 0x12, 0x2b,
+	0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e,
+	0x61, 0x6b, 0x65, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x09, 0x6f,
+	0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x6e,
+	0x61, 0x6b, 0x65, 0x52, 0x09, 0x6f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x10,
+	0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6d, 0x61, 0x70,
+	0x12, 0x29, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x68, 0x72, 0x69, 0x6e, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x68, 0x72,
+	0x69, 0x6e, 0x6b, 0x12, 0x31, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x05, 0x6c, 0x6f, 0x62, 0x62, 0x79, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x62, 0x62, 0x79, 0x52, 0x05, 0x6c, 0x6f, 0x62, 0x62,
+	0x79, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x2b, 0x0a, 0x06, 0x73, 0x6e, 0x61, 0x6b, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53,
+	0x6e, 0x61, 0x6b, 0x65, 0x52, 0x06, 0x73, 0x6e, 0x61, 0x6b, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65,
+	0x6c, 0x69, 0x6e, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65,
+	0x6c, 0x69, 0x6e, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x61, 0x63,
+	0x6b, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x41, 0x63,
+	0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x61,
+	0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x69, 0x6e,
+	0x70, 0x75, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x54, 0x69, 0x63, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x65, 0x63, 0x68, 0x6f, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x65, 0x63, 0x68, 0x6f, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x63, 0x68,
+	0x6f, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x65,
+	0x63, 0x68, 0x6f, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x70, 0x5f, 0x75, 0x6e, 0x63,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6d, 0x61,
+	0x70, 0x55, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x2d, 0x0a, 0x12, 0x70, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x55, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x3f, 0x0a, 0x0f, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x5f, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x65, 0x73, 0x18, 0x19, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x65, 0x73, 0x22, 0xde, 0x02, 0x0a, 0x0a, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d,
+	0x0a, 0x05, 0x63, 0x61, 0x6e, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e,
+	0x64, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x63, 0x61, 0x6e, 0x64, 0x79, 0x12, 0x2a, 0x0a,
+	0x04, 0x70, 0x65, 0x72, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72, 0x6b, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x70, 0x65, 0x72, 0x6b, 0x12, 0x2e, 0x0a, 0x05, 0x63, 0x61, 0x75,
+	0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x61, 0x74, 0x68, 0x43, 0x61, 0x75,
+	0x73, 0x65, 0x52, 0x05, 0x63, 0x61, 0x75, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x70,
+	0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6f, 0x70, 0x70,
+	0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x4e, 0x0a, 0x0a, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x3a, 0x0a, 0x0b, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x06, 0x69, 0x6e,
+	0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52,
+	0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x4c, 0x6f, 0x62, 0x62, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x61,
+	0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x62, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x62, 0x6f,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x74, 0x65, 0x61, 0x6d, 0x22, 0x68, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f,
+	0x62, 0x62, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x4c, 0x6f, 0x62, 0x62, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x52, 0x05, 0x73, 0x6c, 0x6f,
+	0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6f, 0x77, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x03, 0x6f, 0x77, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x22,
+	0x38, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x22, 0xab, 0x01, 0x0a, 0x0a, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x70, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x70,
+	0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x22, 0xb8, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x21, 0x0a, 0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x22, 0xe7, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d,
+	0x61, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2a, 0x94, 0x01, 0x0a,
+	0x0e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x1b, 0x0a, 0x17, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18,
+	0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45,
+	0x5f, 0x4f, 0x4e, 0x47, 0x4f, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f, 0x50, 0x52,
+	0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x52,
+	0x4f, 0x55, 0x4e, 0x44, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x02, 0x12,
+	0x22, 0x0a, 0x1e, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45,
+	0x44, 0x10, 0x03, 0x2a, 0xd9, 0x01, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x72,
+	0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x1b, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50,
+	0x45, 0x52, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f,
+	0x50, 0x45, 0x52, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x57, 0x41, 0x4c, 0x4b, 0x5f, 0x57,
+	0x41, 0x4c, 0x4c, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50,
+	0x45, 0x52, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x41, 0x53, 0x48, 0x10, 0x02, 0x12,
+	0x1a, 0x0a, 0x16, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x52, 0x4b, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x53, 0x48, 0x49, 0x45, 0x4c, 0x44, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x50,
+	0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x52, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x47,
+	0x48, 0x4f, 0x53, 0x54, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f,
+	0x50, 0x45, 0x52, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x41, 0x47, 0x4e, 0x45, 0x54,
+	0x10, 0x05, 0x12, 0x1b, 0x0a, 0x17, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x52, 0x4b,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x56, 0x45, 0x52, 0x53, 0x45, 0x10, 0x06, 0x2a,
+	0x7a, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x45, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x50,
+	0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e,
+	0x4f, 0x52, 0x54, 0x48, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f,
+	0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x4f, 0x55, 0x54, 0x48, 0x10,
+	0x02, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x57, 0x45, 0x53, 0x54, 0x10, 0x03, 0x2a, 0xda, 0x01, 0x0a, 0x0e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x43, 0x61, 0x6e, 0x64, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19,
+	0x0a, 0x15, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x47, 0x52, 0x4f, 0x57, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x52, 0x4f,
+	0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x57, 0x41,
+	0x4c, 0x4b, 0x5f, 0x57, 0x41, 0x4c, 0x4c, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x52, 0x4f,
+	0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x41,
+	0x53, 0x48, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x43, 0x41,
+	0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x48, 0x49, 0x45, 0x4c, 0x44, 0x10,
+	0x03, 0x12, 0x1a, 0x0a, 0x16, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x47, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x04, 0x12, 0x1b, 0x0a,
+	0x17, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x4d, 0x41, 0x47, 0x4e, 0x45, 0x54, 0x10, 0x05, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x52,
+	0x4f, 0x54, 0x4f, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52,
+	0x45, 0x56, 0x45, 0x52, 0x53, 0x45, 0x10, 0x06, 0x2a, 0x98, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x50,
+	0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x50, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x44, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x20, 0x0a,
+	0x1c, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x43, 0x41, 0x4e, 0x44, 0x59, 0x5f, 0x45, 0x41, 0x54, 0x45, 0x4e, 0x10, 0x01, 0x12,
+	0x1e, 0x0a, 0x1a, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x4b, 0x5f, 0x55, 0x53, 0x45, 0x44, 0x10, 0x02, 0x12,
+	0x22, 0x0a, 0x1e, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45,
+	0x44, 0x10, 0x03, 0x2a, 0x88, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x61,
+	0x74, 0x68, 0x43, 0x61, 0x75, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x16, 0x50, 0x52, 0x4f, 0x54, 0x4f,
+	0x5f, 0x44, 0x45, 0x41, 0x54, 0x48, 0x5f, 0x43, 0x41, 0x55, 0x53, 0x45, 0x5f, 0x57, 0x41, 0x4c,
+	0x4c, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x45, 0x41,
+	0x54, 0x48, 0x5f, 0x43, 0x41, 0x55, 0x53, 0x45, 0x5f, 0x53, 0x45, 0x4c, 0x46, 0x10, 0x01, 0x12,
+	0x1e, 0x0a, 0x1a, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x45, 0x41, 0x54, 0x48, 0x5f, 0x43,
+	0x41, 0x55, 0x53, 0x45, 0x5f, 0x4f, 0x50, 0x50, 0x4f, 0x4e, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12,
+	0x1d, 0x0a, 0x19, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x5f, 0x44, 0x45, 0x41, 0x54, 0x48, 0x5f, 0x43,
+	0x41, 0x55, 0x53, 0x45, 0x5f, 0x48, 0x45, 0x41, 0x44, 0x5f, 0x4f, 0x4e, 0x10, 0x03, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_game_network_payload_payload_proto_rawDescOnce sync.Once
+	file_game_network_payload_payload_proto_rawDescData = file_game_network_payload_payload_proto_rawDesc
+)
+
+func file_game_network_payload_payload_proto_rawDescGZIP() []byte {
+	file_game_network_payload_payload_proto_rawDescOnce.Do(func() {
+		file_game_network_payload_payload_proto_rawDescData = protoimpl.X.CompressGZIP(file_game_network_payload_payload_proto_rawDescData)
+	})
+	return file_game_network_payload_payload_proto_rawDescData
+}
+
+var file_game_network_payload_payload_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_game_network_payload_payload_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_game_network_payload_payload_proto_goTypes = []any{
+	(ProtoGameState)(0),       // 0: payload.ProtoGameState
+	(ProtoPerkType)(0),        // 1: payload.ProtoPerkType
+	(ProtoDirection)(0),       // 2: payload.ProtoDirection
+	(ProtoCandyType)(0),       // 3: payload.ProtoCandyType
+	(ProtoEventType)(0),       // 4: payload.ProtoEventType
+	(ProtoDeathCause)(0),      // 5: payload.ProtoDeathCause
+	(*ProtoPosition)(nil),     // 6: payload.ProtoPosition
+	(*ProtoCandy)(nil),        // 7: payload.ProtoCandy
+	(*ProtoPerk)(nil),         // 8: payload.ProtoPerk
+	(*ProtoSnake)(nil),        // 9: payload.ProtoSnake
+	(*ProtoRules)(nil),        // 10: payload.ProtoRules
+	(*ProtoPayload)(nil),      // 11: payload.ProtoPayload
+	(*ProtoEvent)(nil),        // 12: payload.ProtoEvent
+	(*ProtoInput)(nil),        // 13: payload.ProtoInput
+	(*ProtoInputs)(nil),       // 14: payload.ProtoInputs
+	(*ProtoLobbySlot)(nil),    // 15: payload.ProtoLobbySlot
+	(*ProtoLobby)(nil),        // 16: payload.ProtoLobby
+	(*ProtoProfile)(nil),      // 17: payload.ProtoProfile
+	(*ProtoHello)(nil),        // 18: payload.ProtoHello
+	(*ProtoHelloReply)(nil),   // 19: payload.ProtoHelloReply
+	(*ProtoAnnouncement)(nil), // 20: payload.ProtoAnnouncement
+	nil,                       // 21: payload.ProtoSnake.PerksEntry
+}
+var file_game_network_payload_payload_proto_depIdxs = []int32{
+	3,  // 0: payload.ProtoCandy.type:type_name -> payload.ProtoCandyType
+	6,  // 1: payload.ProtoCandy.position:type_name -> payload.ProtoPosition
+	1,  // 2: payload.ProtoPerk.type:type_name -> payload.ProtoPerkType
+	21, // 3: payload.ProtoSnake.perks:type_name -> payload.ProtoSnake.PerksEntry
+	6,  // 4: payload.ProtoSnake.occupied:type_name -> payload.ProtoPosition
+	2,  // 5: payload.ProtoSnake.direction:type_name -> payload.ProtoDirection
+	0,  // 6: payload.ProtoPayload.game_state:type_name -> payload.ProtoGameState
+	7,  // 7: payload.ProtoPayload.candies:type_name -> payload.ProtoCandy
+	9,  // 8: payload.ProtoPayload.player:type_name -> payload.ProtoSnake
+	9,  // 9: payload.ProtoPayload.opponents:type_name -> payload.ProtoSnake
+	10, // 10: payload.ProtoPayload.rules:type_name -> payload.ProtoRules
+	17, // 11: payload.ProtoPayload.profiles:type_name -> payload.ProtoProfile
+	16, // 12: payload.ProtoPayload.lobby:type_name -> payload.ProtoLobby
+	9,  // 13: payload.ProtoPayload.snakes:type_name -> payload.ProtoSnake
+	12, // 14: payload.ProtoPayload.events:type_name -> payload.ProtoEvent
+	6,  // 15: payload.ProtoPayload.removed_candies:type_name -> payload.ProtoPosition
+	4,  // 16: payload.ProtoEvent.type:type_name -> payload.ProtoEventType
+	6,  // 17: payload.ProtoEvent.position:type_name -> payload.ProtoPosition
+	3,  // 18: payload.ProtoEvent.candy:type_name -> payload.ProtoCandyType
+	1,  // 19: payload.ProtoEvent.perk:type_name -> payload.ProtoPerkType
+	5,  // 20: payload.ProtoEvent.cause:type_name -> payload.ProtoDeathCause
+	13, // 21: payload.ProtoInputs.inputs:type_name -> payload.ProtoInput
+	17, // 22: payload.ProtoLobbySlot.profile:type_name -> payload.ProtoProfile
+	15, // 23: payload.ProtoLobby.slots:type_name -> payload.ProtoLobbySlot
+	17, // 24: payload.ProtoHello.profile:type_name -> payload.ProtoProfile
+	8,  // 25: payload.ProtoSnake.PerksEntry.value:type_name -> payload.ProtoPerk
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_game_network_payload_payload_proto_init() }
+func file_game_network_payload_payload_proto_init() {
+	if File_game_network_payload_payload_proto != nil {
+		return
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_game_network_payload_payload_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   6,
+			NumEnums:      6,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,643 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"google.golang.org/protobuf/proto"
+)
+
+const HANDSHAKE_REQ = '?'
+const HANDSHAKE_RESP = '!'
+const HEARTBEAT = '♥'
+
+// LEAVE frees the slot of a client while the game waits in the lobby.
+const LEAVE = '✗'
+
+// ACK is followed by the sequence of the last snapshot the client decoded
+// and the time of the client. The server sends the next snapshots as a delta
+// against it and echoes the time.
+const ACK = '↩'
+
+// INPUT is followed by the ProtoInputs the server has not acknowledged yet.
+const INPUT = '⌨'
+
+// inputQueueSize is how many inputs of a client wait for the next ticks.
+const inputQueueSize = 8
+
+// maxInputLag is how many ticks after the one it was stamped with a queued
+// input is dropped, as long as a newer input follows.
+const maxInputLag = 5
+
+// SESSION_TOKEN_LENGTH is the length of the session token a client gets with
+// the hello reply. A client sends it with its hello to reconnect into its
+// player slot.
+const SESSION_TOKEN_LENGTH = 16
+
+// MaxSpectators is how many spectators can watch a game at once.
+const MaxSpectators = 16
+
+// ClientTimeout is how long a client may stay silent before its slot
+// counts as dropped.
+const ClientTimeout = 3 * time.Second
+
+// peer is the connection to a client.
+type peer interface {
+	Addr() net.Addr
+	// write sends a message to the client, the transport frames and
+	// compresses it.
+	write(message []byte)
+	close()
+}
+
+// session is a client holding a player slot.
+type session struct {
+	peer     peer
+	token    string
+	profile  payload.Profile
+	lastSeen time.Time
+	acked    uint32
+	// echo is the client time of the last ack, echoAt when it arrived.
+	echo     uint64
+	echoAt   time.Time
+	inputAck uint32
+	// applied is the sequence of the last input a tick has used.
+	applied uint32
+	queue   []payload.Input
+	outputs byteBufferChan
+	stop    chan struct{}
+}
+
+// hub holds the player slots and spectators of a transport and handles the
+// messages of their clients.
+type hub struct {
+	// name prefixes the log messages.
+	name        string
+	mu          sync.RWMutex
+	sessions    []*session
+	spectators  []*session
+	clientCount int
+	locked      bool
+}
+
+func (h *hub) IsReady() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.sessions) == h.clientCount
+}
+
+// SetClientCount changes how many clients may join. Clients in slots above
+// the new count are removed.
+func (h *hub) SetClientCount(count int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for len(h.sessions) > count {
+		h.removeSession(len(h.sessions) - 1)
+	}
+	h.clientCount = count
+}
+
+// Lock stops new clients from joining, only known clients can reconnect.
+func (h *hub) Lock() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.locked = true
+}
+
+// Unlock lets new clients join again.
+func (h *hub) Unlock() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.locked = false
+}
+
+// RemoveClient frees the slot of a client, the clients in the following
+// slots move up by one.
+func (h *hub) RemoveClient(slot int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if slot < len(h.sessions) {
+		h.removeSession(slot)
+	}
+}
+
+// removeSession drops a session, h.mu must be locked.
+func (h *hub) removeSession(slot int) {
+	h.sessions[slot].close()
+	h.sessions = slices.Delete(h.sessions, slot, slot+1)
+}
+
+// closeAll drops every session.
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sess := range append(h.sessions, h.spectators...) {
+		sess.close()
+	}
+	h.sessions = nil
+	h.spectators = nil
+}
+
+// Clients returns the current address of every player slot.
+func (h *hub) Clients() []net.Addr {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients := make([]net.Addr, len(h.sessions))
+	for i, sess := range h.sessions {
+		clients[i] = sess.peer.Addr()
+	}
+
+	return clients
+}
+
+// Tokens returns the session token of every player slot.
+func (h *hub) Tokens() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	tokens := make([]string, len(h.sessions))
+	for i, sess := range h.sessions {
+		tokens[i] = sess.token
+	}
+
+	return tokens
+}
+
+// Profiles returns the profile of every connected player slot.
+func (h *hub) Profiles() []payload.Profile {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	profiles := make([]payload.Profile, len(h.sessions))
+	for i, sess := range h.sessions {
+		profiles[i] = sess.profile
+	}
+
+	return profiles
+}
+
+// IsSilent reports if the client of a slot sent nothing for ClientTimeout.
+func (h *hub) IsSilent(slot int) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return slot < len(h.sessions) && time.Since(h.sessions[slot].lastSeen) > ClientTimeout
+}
+
+// ReadSlot returns the oldest queued input of a slot for the game tick, nil
+// if there is none. The server applies one input per tick, so a burst of
+// inputs spreads over the next ticks. Inputs stamped maxInputLag ticks
+// before the tick are dropped while a newer one is queued, so the queue
+// catches up with the client.
+func (h *hub) ReadSlot(slot int, tick uint32) *rune {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if slot >= len(h.sessions) || len(h.sessions[slot].queue) == 0 {
+		return nil
+	}
+
+	sess := h.sessions[slot]
+	// Ticks start at 0 with every round, stamps of an earlier round are
+	// ahead of the tick
+	for len(sess.queue) > 1 && int64(tick)-int64(sess.queue[0].Tick) >= maxInputLag {
+		sess.queue = sess.queue[1:]
+	}
+
+	input := sess.queue[0]
+	sess.queue = sess.queue[1:]
+	sess.applied = max(sess.applied, input.Sequence)
+
+	return &input.Key
+}
+
+// InputApplied returns the sequence of the last input of a slot that was
+// read for a tick, the client predicts the inputs after it.
+func (h *hub) InputApplied(slot int) uint32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if slot >= len(h.sessions) {
+		return 0
+	}
+
+	return h.sessions[slot].applied
+}
+
+// InputAck returns the sequence of the last input the client of a slot
+// sent, the server acknowledges it with the state.
+func (h *hub) InputAck(slot int) uint32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if slot >= len(h.sessions) {
+		return 0
+	}
+
+	return h.sessions[slot].inputAck
+}
+
+// Echo returns the client time of the last ack of a slot and how long ago
+// it arrived, so the client can measure the round trip time.
+func (h *hub) Echo(slot int) (uint64, time.Duration) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if slot >= len(h.sessions) || h.sessions[slot].echo == 0 {
+		return 0, 0
+	}
+
+	return h.sessions[slot].echo, time.Since(h.sessions[slot].echoAt)
+}
+
+func (h *hub) WriteSlot(slot int, content []byte) {
+	h.mu.RLock()
+	if slot >= len(h.sessions) {
+		h.mu.RUnlock()
+		return
+	}
+	outputChan := h.sessions[slot].outputs
+	h.mu.RUnlock()
+
+	offer(outputChan, content)
+}
+
+// Acked returns the last snapshot sequence the client of a slot
+// acknowledged, 0 if none.
+func (h *hub) Acked(slot int) uint32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if slot >= len(h.sessions) {
+		return 0
+	}
+
+	return h.sessions[slot].acked
+}
+
+// WriteSpectators sends every spectator the content encoded for its last
+// acknowledged snapshot and drops the spectators that stayed silent for
+// ClientTimeout.
+func (h *hub) WriteSpectators(encode func(acked uint32) []byte) {
+	h.mu.Lock()
+	h.spectators = slices.DeleteFunc(h.spectators, func(sess *session) bool {
+		if time.Since(sess.lastSeen) <= ClientTimeout {
+			return false
+		}
+
+		log.Printf("%s: Spectator %v timed out", h.name, sess.peer.Addr())
+		sess.close()
+		return true
+	})
+	spectators := slices.Clone(h.spectators)
+	acked := make([]uint32, len(spectators))
+	for i, sess := range spectators {
+		acked[i] = sess.acked
+	}
+	h.mu.Unlock()
+
+	for i, sess := range spectators {
+		offer(sess.outputs, encode(acked[i]))
+	}
+}
+
+// Spectators returns how many spectators are watching.
+func (h *hub) Spectators() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.spectators)
+}
+
+// offer replaces an unsent package with the newer content.
+func offer(outputChan byteBufferChan, content []byte) {
+	select {
+	// Try to write to the channel
+	case outputChan <- byteBuffer{content}:
+	// Otherwise clear channel
+	default:
+		select {
+		case <-outputChan:
+		default:
+		}
+		select {
+		case outputChan <- byteBuffer{content}:
+		default:
+		}
+	}
+}
+
+// receive handles a message of a client.
+func (h *hub) receive(p peer, data []byte) {
+	rune, size := utf8.DecodeRune(data)
+	if rune == utf8.RuneError && size <= 1 {
+		return
+	}
+
+	if rune == HANDSHAKE_REQ {
+		h.handshake(p, data[size:])
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	slot := h.slotOf(p)
+	if slot == -1 {
+		if sess := h.touchSpectator(p, rune == LEAVE); sess != nil && rune == ACK {
+			sess.ack(data[size:])
+		}
+		return
+	}
+
+	sess := h.sessions[slot]
+	sess.lastSeen = time.Now()
+	switch rune {
+	case ACK:
+		sess.ack(data[size:])
+	case INPUT:
+		sess.receive(data[size:])
+	case LEAVE:
+		// LEAVE is queued, the lobby frees the slot
+		sess.enqueue(payload.Input{Key: LEAVE})
+	}
+}
+
+// handshake answers the hello of a client. A known session token moves its
+// slot to the new connection, so a client can reconnect from another port.
+// Unknown clients get a free slot, if any.
+func (h *hub) handshake(p peer, data []byte) {
+	protoHello := &payload.ProtoHello{}
+	if err := proto.Unmarshal(data, protoHello); err != nil {
+		log.Printf("%s: Invalid hello from %v: %v", h.name, p.Addr(), err)
+		reply(p, payload.HelloReply{Reason: "invalid hello message"})
+		return
+	}
+	hello := payload.HelloFromProto(protoHello)
+
+	if hello.ProtocolVersion != payload.ProtocolVersion {
+		log.Printf("%s: Rejected %v with protocol version %d", h.name, p.Addr(), hello.ProtocolVersion)
+		reply(p, payload.HelloReply{
+			Reason: fmt.Sprintf("protocol version %d is not supported, the server uses version %d", hello.ProtocolVersion, payload.ProtocolVersion),
+		})
+		return
+	}
+
+	if hello.Spectator {
+		h.spectate(p, hello)
+		return
+	}
+
+	h.mu.Lock()
+
+	slot := h.slotOf(p)
+	if slot == -1 && hello.SessionToken != "" {
+		slot = slices.IndexFunc(h.sessions, func(sess *session) bool {
+			return sess.token == hello.SessionToken
+		})
+		if slot != -1 {
+			log.Printf("%s: Player %d reconnected from %v", h.name, slot+1, p.Addr())
+			h.sessions[slot].reconnect(p)
+		}
+	}
+
+	if slot == -1 {
+		reason := ""
+		if h.locked {
+			reason = "the game has already started"
+		} else if len(h.sessions) >= h.clientCount {
+			reason = "the game is full"
+		}
+
+		if reason != "" {
+			h.mu.Unlock()
+			log.Printf("%s: Rejected %v: %s", h.name, p.Addr(), reason)
+			reply(p, payload.HelloReply{Reason: reason})
+			return
+		}
+
+		h.sessions = append(h.sessions, newSession(p))
+		slot = len(h.sessions) - 1
+	}
+
+	sess := h.sessions[slot]
+	sess.profile = cleanProfile(hello.Profile, fmt.Sprintf("Spieler %d", slot+1))
+	sess.lastSeen = time.Now()
+	h.mu.Unlock()
+
+	reply(p, payload.HelloReply{
+		Accepted:     true,
+		SessionToken: sess.token,
+		PlayerIndex:  slot,
+	})
+}
+
+// spectate answers the hello of a spectator. Spectators can join at any time
+// without taking a player slot.
+func (h *hub) spectate(p peer, hello payload.Hello) {
+	h.mu.Lock()
+
+	index := slices.IndexFunc(h.spectators, func(sess *session) bool {
+		return samePeer(sess.peer, p) || hello.SessionToken != "" && sess.token == hello.SessionToken
+	})
+
+	if index == -1 {
+		if len(h.spectators) >= MaxSpectators {
+			h.mu.Unlock()
+			log.Printf("%s: Rejected spectator %v: too many spectators", h.name, p.Addr())
+			reply(p, payload.HelloReply{Reason: "too many spectators"})
+			return
+		}
+
+		log.Printf("%s: Spectator joined from %v", h.name, p.Addr())
+		h.spectators = append(h.spectators, newSession(p))
+		index = len(h.spectators) - 1
+	}
+
+	sess := h.spectators[index]
+	sess.reconnect(p)
+	sess.profile = cleanProfile(hello.Profile, "Zuschauer")
+	sess.lastSeen = time.Now()
+	h.mu.Unlock()
+
+	reply(p, payload.HelloReply{
+		Accepted:     true,
+		SessionToken: sess.token,
+	})
+}
+
+func reply(p peer, reply payload.HelloReply) {
+	reply.ProtocolVersion = payload.ProtocolVersion
+
+	data, err := proto.Marshal(reply.ToProto())
+	if err != nil {
+		log.Println("Could not encode hello reply:", err)
+		return
+	}
+
+	p.write(append([]byte(string(HANDSHAKE_RESP)), data...))
+}
+
+// cleanProfile cleans up the profile a client sent.
+func cleanProfile(profile payload.Profile, defaultName string) payload.Profile {
+	name := []rune(strings.TrimSpace(profile.Name))
+	if len(name) > payload.MaxNameLength {
+		name = name[:payload.MaxNameLength]
+	}
+
+	profile.Name = string(name)
+	if profile.Name == "" {
+		profile.Name = defaultName
+	}
+	profile.Color &= 0xFFFFFF
+
+	return profile
+}
+
+// slotOf returns the slot of the client on this connection, -1 if it has
+// none. h.mu must be locked.
+func (h *hub) slotOf(p peer) int {
+	return slices.IndexFunc(h.sessions, func(sess *session) bool {
+		return samePeer(sess.peer, p)
+	})
+}
+
+// touchSpectator keeps a spectator from timing out and returns it, or
+// removes it if it leaves. Spectators send no input. h.mu must be locked.
+func (h *hub) touchSpectator(p peer, leave bool) *session {
+	index := slices.IndexFunc(h.spectators, func(sess *session) bool {
+		return samePeer(sess.peer, p)
+	})
+	if index == -1 {
+		return nil
+	}
+
+	if leave {
+		log.Printf("%s: Spectator %v left", h.name, p.Addr())
+		h.spectators[index].close()
+		h.spectators = slices.Delete(h.spectators, index, index+1)
+		return nil
+	}
+
+	h.spectators[index].lastSeen = time.Now()
+
+	return h.spectators[index]
+}
+
+func samePeer(a, b peer) bool {
+	return a.Addr().String() == b.Addr().String()
+}
+
+func newSession(p peer) *session {
+	sess := &session{
+		peer:     p,
+		token:    newSessionToken(),
+		lastSeen: time.Now(),
+		outputs:  make(byteBufferChan, 1),
+		stop:     make(chan struct{}),
+	}
+
+	go sess.handleWriting(sess.stop, p)
+
+	return sess
+}
+
+func newSessionToken() string {
+	token := make([]byte, SESSION_TOKEN_LENGTH/2)
+	if _, err := rand.Read(token); err != nil {
+		log.Fatal(err)
+	}
+
+	return hex.EncodeToString(token)
+}
+
+// reconnect moves the session to a new connection of its client.
+func (sess *session) reconnect(p peer) {
+	if samePeer(sess.peer, p) {
+		return
+	}
+
+	sess.close()
+	sess.peer = p
+	sess.stop = make(chan struct{})
+	go sess.handleWriting(sess.stop, p)
+}
+
+func (sess *session) close() {
+	close(sess.stop)
+	sess.peer.close()
+}
+
+// receive queues the inputs the server did not get yet. The client repeats
+// its inputs until they are acknowledged, older ones are duplicates.
+func (sess *session) receive(data []byte) {
+	protoInputs := &payload.ProtoInputs{}
+	if err := proto.Unmarshal(data, protoInputs); err != nil {
+		log.Println("Invalid inputs:", err)
+		return
+	}
+
+	for _, input := range payload.InputsFromProto(protoInputs) {
+		if input.Sequence <= sess.inputAck {
+			continue
+		}
+		sess.enqueue(input)
+		sess.inputAck = input.Sequence
+	}
+}
+
+// enqueue adds an input for the next ticks, one is applied per tick. A full
+// queue drops its oldest input.
+func (sess *session) enqueue(input payload.Input) {
+	if len(sess.queue) == inputQueueSize {
+		sess.queue = sess.queue[1:]
+	}
+	sess.queue = append(sess.queue, input)
+}
+
+func (sess *session) handleWriting(stop chan struct{}, p peer) {
+	for {
+		select {
+		case <-stop:
+			return
+		case message := <-sess.outputs:
+			p.write(message[0])
+		}
+	}
+}
+
+// ack reads the sequence and the client time of an ACK.
+func (sess *session) ack(data []byte) {
+	sess.acked = ackedSequence(data, sess.acked)
+	if len(data) >= 12 {
+		sess.echo = binary.BigEndian.Uint64(data[4:12])
+		sess.echoAt = time.Now()
+	}
+}
+
+// ackedSequence reads the sequence of an ACK. Acks of older snapshots may
+// arrive late and are ignored.
+func ackedSequence(data []byte, acked uint32) uint32 {
+	if len(data) < 4 {
+		return acked
+	}
+
+	return max(acked, binary.BigEndian.Uint32(data))
+}
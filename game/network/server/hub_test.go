@@ -0,0 +1,32 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+)
+
+func TestReadSlotDropsLateInputs(t *testing.T) {
+	h := &hub{sessions: []*session{{queue: []payload.Input{
+		{Sequence: 1, Tick: 10, Key: 'w'},
+		{Sequence: 2, Tick: 10, Key: 'a'},
+		{Sequence: 3, Tick: 14, Key: 's'},
+		{Sequence: 4, Tick: 14, Key: 'd'},
+	}}}}
+
+	// The inputs of tick 10 are too late, the first of tick 14 is applied
+	if key := h.ReadSlot(0, 15); key == nil || *key != 's' {
+		t.Fatalf("got %v, expected s", key)
+	}
+	if applied := h.InputApplied(0); applied != 3 {
+		t.Fatalf("applied %d, expected 3", applied)
+	}
+
+	// The last input is never dropped
+	if key := h.ReadSlot(0, 30); key == nil || *key != 'd' {
+		t.Fatalf("got %v, expected d", key)
+	}
+	if key := h.ReadSlot(0, 31); key != nil {
+		t.Fatalf("got %c from an empty queue", *key)
+	}
+}
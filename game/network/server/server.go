@@ -2,75 +2,581 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"math/rand/v2"
 	"net"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/bot"
+	"github.com/apfelfrisch/gosnake/game/network/discovery"
 	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/apfelfrisch/gosnake/game/replay"
 	"google.golang.org/protobuf/proto"
 )
 
-const GameSpeed = time.Second / 10
-const PackageIntervall = GameSpeed / 3
+// DefaultPort is used if an address has no port.
+const DefaultPort = 1200
+
+// MaxPlayers is the most player slots a game can have.
+const MaxPlayers = 9
+
+// PackagesPerTick is how often the state is sent per game tick, to make up
+// for lost packages.
+const PackagesPerTick = 3
+
+// maxEventLog is how many events are kept for clients that did not
+// acknowledge them yet.
+const maxEventLog = 256
 
 type byteBuffer [1][]byte
 type byteBufferChan chan [1][]byte
 
-func New(player int, addr string, game *game.Game) *GameServer {
-	return &GameServer{
-		udp:  NewUdpSever(":1200", player),
-		game: game,
+// READY toggles if a client is ready to start the match.
+const READY = '✓'
+
+// lobbyBroadcastInterval is how often the lobby is sent to the clients.
+const lobbyBroadcastInterval = 50 * time.Millisecond
+
+// NextRoundDelay is how long a server without a host waits after a round or
+// game before it confirms the next one itself.
+const NextRoundDelay = 10 * time.Second
+
+// New serves the game over the transport, see NewTransport.
+func New(player int, transport Transport, game *game.Game) *GameServer {
+	server := &GameServer{
+		transport: transport,
+		game:      game,
+		width:     int(game.Width()),
+		height:    int(game.Height()),
+		standIn:   bot.Greedy{},
+		dropped:   make(map[int]bool),
+		ready:     make(map[string]bool),
+		settings: Settings{
+			Players: player,
+			Rules:   game.Rules(),
+			Map:     game.CustomMap(),
+		},
+	}
+
+	if customMap := game.CustomMap(); customMap != nil {
+		server.mapData, _ = customMap.MarshalText()
 	}
+
+	return server
+}
+
+// Settings are chosen by the host while the server waits in the lobby.
+type Settings struct {
+	Players int
+	// Bots take the last player slots, alternating between the bot kinds.
+	Bots  int
+	Rules game.Rules
+	// Map is played in every level instead of the level maps, if set.
+	Map     *game.Map
+	MapName string
+}
+
+type NamedMap struct {
+	Name string
+	// Map is nil for the level maps.
+	Map *game.Map
 }
 
 type GameServer struct {
-	udp             *UdpServer
+	mu              sync.Mutex
+	transport       Transport
 	game            *game.Game
+	width           int
+	height          int
+	mapData         []byte
+	settings        Settings
+	bots            []bot.Bot
+	standIn         bot.Bot
+	dropped         map[int]bool
+	ready           map[string]bool
+	autoStart       bool
+	readyStart      bool
+	rotation        []NamedMap
+	rotationIndex   int
+	name            string
+	started         bool
+	confirm         bool
+	record          func() (io.Writer, error)
+	recorder        *replay.Recorder
 	lastUpdate      time.Time
 	lastPackageSend time.Time
+	sequence        uint32
+	snapshots       [payload.SnapshotHistory]snapshot
+	// ticks counts the steps of all matches, resent states share it.
+	ticks uint32
+	// events are the latest events, eventSequence numbers them.
+	events        []payload.Event
+	eventSequence uint32
+	// finishedAt is when the current round or game finished.
+	finishedAt time.Time
+}
+
+// snapshot holds a sent state, the clients acknowledge it to get deltas
+// against it.
+type snapshot struct {
+	sequence uint32
+	players  []game.Snake
+	profiles []payload.Profile
+	candies  []game.Candy
+	mapData  []byte
+	rules    game.Rules
+	// events is the sequence of the last event sent with the state.
+	events uint32
+}
+
+// payload returns the state as the baseline payload of a slot, -1 for
+// spectators.
+func (snap snapshot) payload(slot int) payload.Payload {
+	pl := payload.Payload{
+		Sequence: snap.sequence,
+		Candies:  snap.candies,
+		Map:      snap.mapData,
+		Rules:    snap.rules,
+	}
+
+	if slot < 0 {
+		pl.Snakes = snap.players
+		pl.Profiles = snap.profiles
+	} else {
+		pl.Player, pl.Opponents = perspective(snap.players, slot)
+		pl.Profiles = profilePerspective(snap.profiles, slot)
+	}
+
+	return pl
+}
+
+// Configure applies new lobby settings and builds a new game for them.
+// Every client has to confirm again that it is ready.
+func (s *GameServer) Configure(settings Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return errors.New("the match has already started")
+	}
+	if settings.Players < 1 || settings.Players > MaxPlayers {
+		return fmt.Errorf("player count must be between 1 and %d", MaxPlayers)
+	}
+	if settings.Bots < 0 || settings.Bots >= settings.Players {
+		return errors.New("at least one player slot must be left for a client")
+	}
+	if clients := len(s.transport.Clients()); settings.Players-settings.Bots < clients {
+		return fmt.Errorf("%d clients already joined", clients)
+	}
+	if err := settings.Rules.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.newGame(settings); err != nil {
+		return err
+	}
+
+	s.bots = s.bots[:min(len(s.bots), settings.Bots)]
+	for i := len(s.bots); i < settings.Bots; i++ {
+		s.bots = append(s.bots, bot.New(bot.Kind(i%2)))
+	}
+
+	s.settings = settings
+	s.transport.SetClientCount(settings.Players - settings.Bots)
+	clear(s.ready)
+
+	return nil
+}
+
+func (s *GameServer) Settings() Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.settings
+}
+
+// newGame replaces the game with a new one for the settings.
+func (s *GameServer) newGame(settings Settings) error {
+	seed := rand.Uint64()
+	if settings.Map != nil {
+		s.game = game.NewCustomGame(settings.Players, settings.Map, settings.Rules, seed)
+		s.mapData, _ = settings.Map.MarshalText()
+
+		return nil
+	}
+
+	g, err := game.NewGame(settings.Players, s.width, s.height, settings.Rules, seed)
+	if err != nil {
+		return err
+	}
+	s.game = g
+	s.mapData = nil
+
+	return nil
+}
+
+// MapRotation moves on to the next map whenever a finished game is started
+// again. The first map should be the one of the settings.
+func (s *GameServer) MapRotation(maps []NamedMap) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rotation = maps
+	s.rotationIndex = 0
+}
+
+// nextMap replaces the finished game with a new one on the next map of the
+// rotation.
+func (s *GameServer) nextMap() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.rotate() {
+		return
+	}
+
+	s.stopRecording()
+	s.startRecording()
+}
+
+// rotate replaces the game with a new one on the next map of the rotation,
+// s.mu must be locked. It reports if the map could be loaded.
+func (s *GameServer) rotate() bool {
+	s.rotationIndex = (s.rotationIndex + 1) % len(s.rotation)
+	next := s.rotation[s.rotationIndex]
+	s.settings.Map, s.settings.MapName = next.Map, next.Name
+	if err := s.newGame(s.settings); err != nil {
+		log.Printf("Could not load map %s: %s", next.Name, err)
+		return false
+	}
+
+	log.Printf("Next map: %s", next.Name)
+
+	return true
+}
+
+// AutoStart starts the match as soon as every player slot is taken, without
+// waiting for the clients to be ready.
+func (s *GameServer) AutoStart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.autoStart = true
+}
+
+// StartWhenReady starts the match as soon as every player slot is taken and
+// every client is ready, for servers without a host. Later rounds start
+// after NextRoundDelay, and the lobby opens again once every client left.
+func (s *GameServer) StartWhenReady() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.readyStart = true
+}
+
+// Start begins the match once every player slot is taken and every client
+// is ready.
+func (s *GameServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.canStart(); err != nil {
+		return err
+	}
+
+	s.begin()
+
+	return nil
+}
+
+// canStart checks if the match can begin, s.mu must be locked.
+func (s *GameServer) canStart() error {
+	if s.started {
+		return errors.New("the match has already started")
+	}
+	if !s.transport.IsReady() {
+		return errors.New("not every player has joined")
+	}
+	for _, token := range s.transport.Tokens() {
+		if !s.ready[token] {
+			return errors.New("not every player is ready")
+		}
+	}
+
+	return nil
 }
 
-func (s *GameServer) Addr() *net.UDPAddr {
-	return s.udp.addr
+// begin leaves the lobby, s.mu must be locked.
+func (s *GameServer) begin() {
+	s.started = true
+	s.confirm = true
+	s.transport.Lock()
+	s.startRecording()
+	log.Printf("Match started with %d players", len(s.game.Players()))
 }
 
-func (s *GameServer) Clients() []*net.UDPAddr {
-	return s.udp.clients
+// Announce makes the server findable on the LAN under name while the lobby
+// is open.
+func (s *GameServer) Announce(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.name = name
+}
+
+func (s *GameServer) announcement() (payload.Announcement, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return payload.Announcement{}, false
+	}
+
+	return payload.Announcement{
+		Name:     s.name,
+		Network:  s.transport.Network(),
+		Port:     port(s.transport.Addr()),
+		MapName:  s.settings.MapName,
+		Rules:    s.settings.Rules.Name,
+		Players:  len(s.transport.Clients()) + len(s.bots),
+		Capacity: s.settings.Players,
+	}, true
+}
+
+func (s *GameServer) Bots() []bot.Bot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bots
+}
+
+// Profiles returns the profile of every player slot, empty for slots still
+// waiting for a client.
+func (s *GameServer) Profiles() []payload.Profile {
+	profiles := make([]payload.Profile, len(s.game.Players()))
+	copy(profiles, s.transport.Profiles())
+
+	botOffset := len(profiles) - len(s.bots)
+	for i := range s.bots {
+		profiles[botOffset+i] = payload.Profile{Name: fmt.Sprintf("Bot %d", i+1)}
+	}
+
+	return profiles
+}
+
+// Team returns the team of a player slot, 0 if the game has no teams.
+// Client slots are filled in connection order, bots take the last slots.
+func (s *GameServer) Team(slot int) uint8 {
+	return s.game.Rules().TeamOf(slot)
+}
+
+// Record writes every game step to the writer returned by open, so the match
+// can be replayed later. open is called when the match starts.
+func (s *GameServer) Record(open func() (io.Writer, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.record = open
+}
+
+// Addr is the address the server is bound to, with the actual port if it
+// listens on port 0. It is nil until the server listens.
+func (s *GameServer) Addr() net.Addr {
+	return s.transport.Addr()
+}
+
+// port returns the port of an address, 0 if it has none.
+func port(addr net.Addr) int {
+	_, portText, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(portText)
+
+	return port
+}
+
+func (s *GameServer) Clients() []net.Addr {
+	return s.transport.Clients()
 }
 
 func (s *GameServer) IsListining() bool {
-	return s.udp.IsListining()
+	return s.transport.IsListining()
 }
 
 func (s *GameServer) Ready() bool {
-	return s.udp.IsReady()
+	return s.transport.IsReady()
+}
+
+func (s *GameServer) Listen() error {
+	return s.transport.Listen()
 }
 
-func (s *GameServer) RunBackground(ctx context.Context) {
+// RunBackground binds the address before it returns, so Addr is known.
+func (s *GameServer) RunBackground(ctx context.Context) error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+
 	go func() {
 		s.Run(ctx)
 	}()
+
+	return nil
 }
 
 func (s *GameServer) Run(ctx context.Context) {
-	s.udp.Listen(ctx)
+	defer s.stopRecording()
+
+	if !s.IsListining() {
+		if err := s.Listen(); err != nil {
+			log.Println("Could not start server:", err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	if s.name != "" {
+		go discovery.Announce(ctx, s.announcement)
+	}
+	s.mu.Unlock()
+
+	for {
+		if !s.waitInLobby(ctx) {
+			s.transport.Disconnect()
+			return
+		}
+
+		if !s.play(ctx) {
+			return
+		}
+	}
+}
 
+// play updates the game while every player slot is taken. It reports if the
+// server went back to the lobby.
+func (s *GameServer) play(ctx context.Context) bool {
 	for s.Ready() {
 		select {
 		case <-ctx.Done():
 			s.game.Reset()
-			s.udp.Disconnect()
-			return
+			s.transport.Disconnect()
+			return false
 		default:
 			s.Update()
 		}
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.started
+}
+
+// waitInLobby handles joining, leaving and ready clients until the match
+// starts. It returns false if ctx is done first.
+func (s *GameServer) waitInLobby(ctx context.Context) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		default:
+		}
+
+		if s.updateLobby() {
+			return true
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// updateLobby reports if the match has started.
+func (s *GameServer) updateLobby() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return true
+	}
+
+	tokens := s.transport.Tokens()
+	for slot := len(tokens) - 1; slot >= 0; slot-- {
+		if s.transport.IsSilent(slot) {
+			log.Printf("Player %d timed out in the lobby", slot+1)
+			s.leave(slot, tokens[slot])
+			continue
+		}
+
+		for key := s.transport.ReadSlot(slot, 0); key != nil; key = s.transport.ReadSlot(slot, 0) {
+			switch *key {
+			case READY:
+				s.ready[tokens[slot]] = !s.ready[tokens[slot]]
+			case LEAVE:
+				log.Printf("Player %d left the lobby", slot+1)
+				s.leave(slot, tokens[slot])
+			}
+		}
+	}
+
+	if s.autoStart && s.transport.IsReady() || s.readyStart && s.canStart() == nil {
+		s.begin()
+		return true
+	}
+
+	if time.Since(s.lastPackageSend) > lobbyBroadcastInterval {
+		s.broadcastState()
+		s.lastPackageSend = time.Now()
+	}
+
+	return false
+}
+
+// leave frees the slot of a client, s.mu must be locked.
+func (s *GameServer) leave(slot int, token string) {
+	s.transport.RemoveClient(slot)
+	delete(s.ready, token)
+}
+
+// lobby lists the player slots as seen by the client in slot own, -1 for
+// spectators.
+func (s *GameServer) lobby(own int) *payload.Lobby {
+	tokens := s.transport.Tokens()
+	profiles := s.Profiles()
+	botOffset := len(profiles) - len(s.bots)
+
+	lobby := &payload.Lobby{
+		Slots:   make([]payload.LobbySlot, len(profiles)),
+		Own:     own,
+		MapName: s.settings.MapName,
+	}
+	for i, profile := range profiles {
+		lobby.Slots[i] = payload.LobbySlot{
+			Profile:   profile,
+			Connected: i < len(tokens),
+			Ready:     i < len(tokens) && s.ready[tokens[i]],
+			Bot:       i >= botOffset,
+			Team:      s.Team(i),
+		}
+	}
+
+	return lobby
 }
 
 func (s *GameServer) Update() {
-	if time.Since(s.lastUpdate) < GameSpeed {
+	gameSpeed := s.game.Rules().TickDuration()
+
+	if time.Since(s.lastUpdate) < gameSpeed {
 		// Resend state to because of package lost
-		if time.Since(s.lastPackageSend) > PackageIntervall {
+		if time.Since(s.lastPackageSend) > gameSpeed/PackagesPerTick {
 			s.broadcastState()
 			s.lastPackageSend = time.Now()
 		}
@@ -79,68 +585,372 @@ func (s *GameServer) Update() {
 		return
 	}
 
-	for connIndex, conn := range s.udp.clients {
-		pressedKey := s.udp.ReadConn(conn)
-
-		if pressedKey == nil {
-			continue
+	inputs := make([]game.Input, len(s.game.Players()))
+	for slot := range s.transport.Clients() {
+		if s.dropClient(slot) {
+			// A bot plays for the dropped client until it reconnects
+			inputs[slot] = s.standIn.Input(s.game, slot)
+		} else if pressedKey := s.transport.ReadSlot(slot, s.game.Ticks()); pressedKey != nil {
+			inputs[slot] = game.Input(*pressedKey)
 		}
+	}
 
-		if s.game.State() != game.Ongoing {
-			if *pressedKey == rune('↵') {
-				if s.game.State() == game.Paused {
-					s.game.TooglePaused()
-				} else {
-					s.game.Reset()
-				}
-				return
-			}
-			continue
-		}
+	// Everyone was ready in the lobby, so the first step starts the match
+	if s.confirm {
+		inputs[0] = game.InputConfirm
+		s.confirm = false
+	}
 
-		if *pressedKey == rune('w') {
-			s.game.ChangeDirection(connIndex, game.North)
-		} else if *pressedKey == rune('s') {
-			s.game.ChangeDirection(connIndex, game.South)
-		} else if *pressedKey == rune('a') {
-			s.game.ChangeDirection(connIndex, game.West)
-		} else if *pressedKey == rune('d') {
-			s.game.ChangeDirection(connIndex, game.East)
-		} else if *pressedKey == rune(' ') {
-			s.game.Dash(connIndex)
+	if s.readyStart && s.awaitNextRound(inputs) {
+		return
+	}
+
+	if s.game.State() == game.GameFinished && len(s.rotation) > 1 && slices.Contains(inputs, game.InputConfirm) {
+		s.nextMap()
+		s.ticks++
+		s.broadcastState()
+		s.lastUpdate = time.Now()
+		s.lastPackageSend = time.Now()
+		return
+	}
+
+	botOffset := len(inputs) - len(s.bots)
+	for i, b := range s.bots {
+		inputs[botOffset+i] = b.Input(s.game, botOffset+i)
+	}
+
+	if s.recorder != nil {
+		if err := s.recorder.Record(inputs); err != nil {
+			log.Println("Could not record game step:", err)
+			s.stopRecording()
 		}
 	}
 
-	s.game.Tick()
+	s.game.Step(inputs)
+	s.ticks++
+	for _, event := range s.game.DrainEvents() {
+		if death, ok := event.(game.PlayerDied); ok {
+			log.Printf("Player %d died at %v, cause: %v, opponent: %d", death.Player+1, death.Position, death.Cause, death.Opponent+1)
+		}
+		s.logEvent(event)
+	}
 	s.broadcastState()
 
 	s.lastUpdate = time.Now()
 	s.lastPackageSend = time.Now()
 }
 
+// awaitNextRound confirms the next round or game for servers without a host,
+// whose stand-in bots never confirm. If every client is gone after the game,
+// it goes back to the lobby instead and reports true.
+func (s *GameServer) awaitNextRound(inputs []game.Input) bool {
+	state := s.game.State()
+	if state != game.RoundFinished && state != game.GameFinished {
+		s.finishedAt = time.Time{}
+		return false
+	}
+	if s.finishedAt.IsZero() {
+		s.finishedAt = time.Now()
+	}
+
+	if state == game.GameFinished && s.allDropped() {
+		s.backToLobby()
+		return true
+	}
+
+	if time.Since(s.finishedAt) > NextRoundDelay {
+		inputs[0] = game.InputConfirm
+	}
+
+	return false
+}
+
+// allDropped reports if the client of every player slot timed out.
+func (s *GameServer) allDropped() bool {
+	for slot := range s.transport.Clients() {
+		if !s.dropped[slot] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// backToLobby frees every player slot and opens the lobby again with a new
+// game on the next map.
+func (s *GameServer) backToLobby() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopRecording()
+
+	for slot := len(s.transport.Clients()) - 1; slot >= 0; slot-- {
+		s.transport.RemoveClient(slot)
+	}
+	clear(s.dropped)
+	clear(s.ready)
+	s.finishedAt = time.Time{}
+
+	if len(s.rotation) <= 1 || !s.rotate() {
+		if err := s.newGame(s.settings); err != nil {
+			log.Println("Could not create a new game:", err)
+		}
+	}
+
+	s.started = false
+	s.transport.Unlock()
+
+	log.Println("Every player is gone, back to the lobby")
+}
+
+// dropClient reports if the client of a slot timed out and logs when a
+// client drops or comes back.
+func (s *GameServer) dropClient(slot int) bool {
+	silent := s.transport.IsSilent(slot)
+	if silent != s.dropped[slot] {
+		if silent {
+			log.Printf("Player %d timed out, a bot takes over", slot+1)
+		} else {
+			log.Printf("Player %d is back", slot+1)
+		}
+		s.dropped[slot] = silent
+	}
+
+	return silent
+}
+
+func (s *GameServer) startRecording() {
+	if s.record == nil {
+		return
+	}
+
+	w, err := s.record()
+	if err != nil {
+		log.Println("Could not record match:", err)
+		return
+	}
+
+	if s.recorder, err = replay.NewRecorder(w, s.game); err != nil {
+		log.Println("Could not record match:", err)
+		if closer, ok := w.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+func (s *GameServer) stopRecording() {
+	if s.recorder == nil {
+		return
+	}
+
+	if err := s.recorder.Close(); err != nil {
+		log.Println("Could not close recording:", err)
+	}
+	s.recorder = nil
+}
+
 func (s *GameServer) broadcastState() {
 	players := s.game.Players()
-	for i, conn := range s.udp.clients {
-		opponents := make([]game.Snake, 0, len(players)-1)
-		opponents = append(opponents, players[:i]...)
-		opponents = append(opponents, players[i+1:]...)
+	profiles := s.Profiles()
+	s.remember(players, profiles)
 
-		var bytes []byte
-		var err error
+	for i := range s.transport.Clients() {
+		player, opponents := perspective(players, i)
+		echo, held := s.transport.Echo(i)
+		acked := s.transport.Acked(i)
 
 		pl := payload.Payload{
-			MapLevel:  s.game.Level(),
-			GameState: s.game.State(),
-			Candies:   s.game.Candies(),
-			Player:    players[i],
-			Opponents: opponents,
+			MapLevel:     s.game.Level(),
+			GameState:    s.game.State(),
+			Candies:      s.game.Candies(),
+			Player:       player,
+			Opponents:    opponents,
+			Map:          s.mapData,
+			Rules:        s.game.Rules(),
+			Shrink:       s.game.Shrink(),
+			Profiles:     profilePerspective(profiles, i),
+			Sequence:     s.sequence,
+			InputAck:     s.transport.InputAck(i),
+			InputApplied: s.transport.InputApplied(i),
+			Tick:         s.game.Ticks(),
+			ServerTick:   s.ticks,
+			ServerTime:   time.Now().UnixMicro(),
+			EchoTime:     echo,
+			EchoDelay:    uint32(held.Microseconds()),
+			Events:       s.pendingEvents(acked, i),
+		}
+		if !s.started {
+			pl.Lobby = s.lobby(i)
 		}
 
-		bytes, err = proto.Marshal(pl.ToProto())
-		if err != nil {
-			panic(err)
+		protoPayload := pl.ToProto()
+		if base, ok := s.baseline(acked); ok && i < len(base.players) {
+			protoPayload = pl.ToDeltaProto(base.payload(i))
 		}
 
-		s.udp.WriteConn(conn, bytes)
+		s.transport.WriteSlot(i, marshal(protoPayload))
 	}
+
+	if s.transport.Spectators() > 0 {
+		s.broadcastSpectators(players, profiles)
+	}
+}
+
+// broadcastSpectators sends every snake in slot order to the spectators.
+func (s *GameServer) broadcastSpectators(players []game.Snake, profiles []payload.Profile) {
+	pl := payload.Payload{
+		MapLevel:   s.game.Level(),
+		GameState:  s.game.State(),
+		Candies:    s.game.Candies(),
+		Map:        s.mapData,
+		Rules:      s.game.Rules(),
+		Shrink:     s.game.Shrink(),
+		Profiles:   profiles,
+		Spectator:  true,
+		Snakes:     players,
+		Sequence:   s.sequence,
+		Tick:       s.game.Ticks(),
+		ServerTick: s.ticks,
+		ServerTime: time.Now().UnixMicro(),
+	}
+	if !s.started {
+		pl.Lobby = s.lobby(-1)
+	}
+
+	s.transport.WriteSpectators(func(acked uint32) []byte {
+		pl := pl
+		pl.Events = s.pendingEvents(acked, -1)
+
+		base, ok := s.baseline(acked)
+		if !ok {
+			return marshal(pl.ToProto())
+		}
+
+		return marshal(pl.ToDeltaProto(base.payload(-1)))
+	})
+}
+
+// remember numbers the state about to be sent and keeps it, so later states
+// can be sent as a delta against it.
+func (s *GameServer) remember(players []game.Snake, profiles []payload.Profile) {
+	s.sequence++
+
+	snakes := make([]game.Snake, len(players))
+	for i, player := range players {
+		snakes[i] = player
+		snakes[i].Occupied = slices.Clone(player.Occupied)
+		snakes[i].Perks = maps.Clone(player.Perks)
+	}
+
+	s.snapshots[s.sequence%payload.SnapshotHistory] = snapshot{
+		sequence: s.sequence,
+		players:  snakes,
+		profiles: profiles,
+		candies:  slices.Clone(s.game.Candies()),
+		mapData:  s.mapData,
+		rules:    s.game.Rules(),
+		events:   s.eventSequence,
+	}
+}
+
+// logEvent numbers an event of the game and keeps it until the clients
+// acknowledged it.
+func (s *GameServer) logEvent(event game.Event) {
+	s.eventSequence++
+	s.events = append(s.events, payload.Event{Sequence: s.eventSequence, Event: event})
+	if len(s.events) > maxEventLog {
+		s.events = s.events[len(s.events)-maxEventLog:]
+	}
+}
+
+// pendingEvents returns the events after the ones sent with the
+// acknowledged snapshot, as seen from a slot. Spectators pass -1 and get the
+// players in slot order.
+func (s *GameServer) pendingEvents(acked uint32, slot int) []payload.Event {
+	var sent uint32
+	if base, ok := s.baseline(acked); ok {
+		sent = base.events
+	}
+
+	var pending []payload.Event
+	for _, event := range s.events {
+		if event.Sequence > sent {
+			pending = append(pending, payload.Event{Sequence: event.Sequence, Event: eventPerspective(event.Event, slot)})
+		}
+	}
+
+	return pending
+}
+
+// baseline returns the acknowledged snapshot, if it is still kept. Without
+// one the client gets a keyframe.
+func (s *GameServer) baseline(acked uint32) (snapshot, bool) {
+	if acked == 0 || s.sequence-acked >= payload.SnapshotHistory {
+		return snapshot{}, false
+	}
+
+	base := s.snapshots[acked%payload.SnapshotHistory]
+
+	return base, base.sequence == acked
+}
+
+// perspective returns the snake of a slot and its opponents in slot order.
+func perspective(players []game.Snake, slot int) (game.Snake, []game.Snake) {
+	opponents := make([]game.Snake, 0, len(players)-1)
+	opponents = append(opponents, players[:slot]...)
+	opponents = append(opponents, players[slot+1:]...)
+
+	return players[slot], opponents
+}
+
+// profilePerspective returns the profile of a slot, followed by the others
+// in slot order.
+func profilePerspective(profiles []payload.Profile, slot int) []payload.Profile {
+	own := make([]payload.Profile, 0, len(profiles))
+	own = append(own, profiles[slot])
+	own = append(own, profiles[:slot]...)
+	own = append(own, profiles[slot+1:]...)
+
+	return own
+}
+
+// eventPerspective changes the slots of an event into indexes of the
+// profiles a slot gets, its own player first.
+func eventPerspective(event game.Event, slot int) game.Event {
+	index := func(player int) int {
+		switch {
+		case slot < 0 || player < 0:
+			return player
+		case player == slot:
+			return 0
+		case player < slot:
+			return player + 1
+		default:
+			return player
+		}
+	}
+
+	switch e := event.(type) {
+	case game.PlayerDied:
+		e.Player = index(e.Player)
+		e.Opponent = index(e.Opponent)
+		return e
+	case game.CandyEaten:
+		e.Player = index(e.Player)
+		return e
+	case game.PerkUsed:
+		e.Player = index(e.Player)
+		return e
+	}
+
+	return event
+}
+
+func marshal(protoPayload *payload.ProtoPayload) []byte {
+	bytes, err := proto.Marshal(protoPayload)
+	if err != nil {
+		panic(err)
+	}
+
+	return bytes
 }
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game"
+	"github.com/apfelfrisch/gosnake/game/network/client"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+)
+
+func TestListenOnFreePort(t *testing.T) {
+	for _, network := range []string{"udp", "tcp", "ws"} {
+		t.Run(network, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			g, err := game.NewGame(1, 50, 50, game.DefaultRules(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			transport, err := NewTransport(network, ":0", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gameServer := New(1, transport, g)
+			if err := gameServer.RunBackground(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			_, port, err := net.SplitHostPort(gameServer.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if port == "0" {
+				t.Fatal("the server did not report the bound port")
+			}
+
+			gameClient, err := client.Connect(ctx, network, net.JoinHostPort("127.0.0.1", port), 50, 50, payload.Profile{Name: "Test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer gameClient.Leave()
+
+			if clients := gameServer.Clients(); len(clients) != 1 {
+				t.Fatalf("got %d clients, expected 1", len(clients))
+			}
+		})
+	}
+}
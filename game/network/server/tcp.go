@@ -1,155 +1,124 @@
 package server
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/binary"
+	"errors"
 	"log"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/apfelfrisch/gosnake/game/network/framing"
 	"github.com/golang/snappy"
 )
 
-func NewTcpSever(addr string, connCount int) *Tcp {
-	var inputConnChans []chan rune
-	var outputConnChans []byteBufferChan
-
-	for i := 0; i < connCount; i++ {
-		inputConnChans = append(inputConnChans, make(chan rune, 3))
+// NewTcpSever serves the clients over TCP, for networks that block UDP.
+func NewTcpSever(addr string, connCount int) *TcpServer {
+	return &TcpServer{
+		hub:        hub{name: "TCP-SERVER", clientCount: connCount},
+		listenAddr: addr,
 	}
+}
 
-	for i := 0; i < connCount; i++ {
-		outputConnChans = append(outputConnChans, make(byteBufferChan, 1))
-	}
+type TcpServer struct {
+	hub
+	listenAddr string
+	listener   net.Listener
+	isOpen     bool
+}
 
-	tcpAddr, err := net.ResolveTCPAddr("tcp4", addr)
-	if err != nil {
-		log.Fatal(err)
-	}
+// tcpPeer is a client connection. A client that reconnects gets a new one.
+type tcpPeer struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
 
-	return &Tcp{
-		addr:        tcpAddr,
-		inputChans:  inputConnChans,
-		outputChans: outputConnChans,
-	}
+func (p *tcpPeer) Addr() net.Addr {
+	return p.conn.RemoteAddr()
 }
 
-type Tcp struct {
-	addr        *net.TCPAddr
-	inputChans  []chan rune
-	outputChans []byteBufferChan
-	conns       tcpConns
+func (p *tcpPeer) write(message []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.conn.SetWriteDeadline(time.Now().Add(ClientTimeout))
+	if err := framing.WriteFrame(p.conn, snappy.Encode(nil, message)); err != nil {
+		p.conn.Close()
+	}
 }
 
-func (s *Tcp) Ready() bool {
-	return len(s.inputChans) == s.conns.count()
+func (p *tcpPeer) close() {
+	p.conn.Close()
 }
 
-func (s *Tcp) ReadConn(connIndex int) *rune {
-	select {
-	case value := <-s.inputChans[connIndex]:
-		return &value
-	default:
-		return nil
+func (s *TcpServer) Disconnect() {
+	if s.isOpen {
+		s.isOpen = false
+		log.Println("Connection closed")
+		s.listener.Close()
+		s.closeAll()
 	}
 }
 
-func (s *Tcp) WriteConn(connIndex int, content []byte) {
+func (s *TcpServer) Network() string {
+	return "tcp"
+}
 
-	select {
-	// Try to write to the channel
-	case s.outputChans[connIndex] <- byteBuffer{content}:
-	// Otherwise clear channel
-	default:
-		<-s.outputChans[connIndex]
-		s.outputChans[connIndex] <- byteBuffer{content}
-	}
+func (s *TcpServer) IsListining() bool {
+	return s.isOpen
 }
 
-func (s *Tcp) Listen() {
-	listener, err := net.ListenTCP("tcp", s.addr)
-	defer listener.Close()
+// Addr is the address the server is bound to, nil before Listen.
+func (s *TcpServer) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
 
+// Listen opens the socket and handles the clients in the background until
+// Disconnect is called. Port 0 binds a free port.
+func (s *TcpServer) Listen() error {
+	var err error
+	s.listener, err = net.Listen("tcp", s.listenAddr)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	for i, inputChan := range s.inputChans {
-		// Accept new connections
-		conn, err := listener.Accept()
-		s.conns.add(conn)
+	s.isOpen = true
 
-		if err != nil {
-			log.Fatal("Could not connect: " + err.Error())
-		}
+	go s.handleAccepting()
 
-		// Handle new connections in a Goroutine for concurrency
-		go s.handleSeverReading(conn, inputChan)
-		go s.handleServerWriting(conn, s.outputChans[i])
-	}
+	return nil
 }
 
-func (s *Tcp) Shutdown() {
-	s.conns.reset()
-}
-
-func (s *Tcp) handleSeverReading(conn net.Conn, inputChan chan rune) {
+func (s *TcpServer) handleAccepting() {
 	for {
-		// Read from the connection untill a new line is send
-		data, _, err := bufio.NewReader(conn).ReadRune()
-		if err != nil {
-			s.Shutdown()
+		conn, err := s.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
 			return
 		}
-
-		select {
-		case inputChan <- data:
-		default:
-			<-inputChan
-			inputChan <- data
+		if err != nil {
+			log.Println("TCP-SERVER:", err)
+			continue
 		}
-	}
-}
-
-func (s *Tcp) handleServerWriting(conn net.Conn, outputChan byteBufferChan) {
-	for {
-		message := <-outputChan
-
-		compressed := snappy.Encode(nil, message[0])
-
-		var lengthBuffer bytes.Buffer
-		binary.Write(&lengthBuffer, binary.BigEndian, uint32(len(compressed)))
 
-		conn.Write(lengthBuffer.Bytes())
-		conn.Write(compressed)
+		go s.handleSeverReading(&tcpPeer{conn: conn})
 	}
 }
 
-type tcpConns struct {
-	conns []net.Conn
-	mu    sync.Mutex
-}
-
-func (tc *tcpConns) add(conn net.Conn) {
-	tc.mu.Lock()
-	tc.conns = append(tc.conns, conn)
-	tc.mu.Unlock()
-}
-
-func (tc *tcpConns) count() int {
-	return len(tc.conns)
-}
+// handleSeverReading passes the messages of a connection on until it closes
+// or stays silent for ClientTimeout.
+func (s *TcpServer) handleSeverReading(p *tcpPeer) {
+	defer p.close()
 
-func (tc *tcpConns) get() []net.Conn {
-	return tc.conns
-}
+	for {
+		p.conn.SetReadDeadline(time.Now().Add(ClientTimeout))
+		message, err := framing.ReadFrame(p.conn)
+		if err != nil {
+			return
+		}
 
-func (tc *tcpConns) reset() {
-	tc.mu.Lock()
-	for _, conn := range tc.conns {
-		conn.Close()
+		s.receive(p, message)
 	}
-	tc.conns = []net.Conn{}
-	tc.mu.Unlock()
 }
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+)
+
+// Transport accepts clients into the player slots and spectator seats,
+// reads their inputs and writes the state to them.
+type Transport interface {
+	// Network is the network clients connect with, as in NewTransport.
+	Network() string
+	Listen() error
+	Disconnect()
+	IsListining() bool
+	// Addr is the address the transport is bound to, nil before Listen.
+	Addr() net.Addr
+
+	// IsReady reports if every player slot is taken.
+	IsReady() bool
+	SetClientCount(count int)
+	// Lock stops new clients from joining, only known clients can
+	// reconnect.
+	Lock()
+	// Unlock lets new clients join again.
+	Unlock()
+	RemoveClient(slot int)
+	Clients() []net.Addr
+	Tokens() []string
+	Profiles() []payload.Profile
+	IsSilent(slot int) bool
+
+	// ReadSlot returns the next input of a slot for the game tick, one
+	// input is applied per tick.
+	ReadSlot(slot int, tick uint32) *rune
+	InputAck(slot int) uint32
+	InputApplied(slot int) uint32
+	Echo(slot int) (uint64, time.Duration)
+	WriteSlot(slot int, content []byte)
+	Acked(slot int) uint32
+	WriteSpectators(encode func(acked uint32) []byte)
+	Spectators() int
+}
+
+// NewTransport returns the transport for network, "udp", "tcp" or "ws".
+func NewTransport(network, addr string, clientCount int) (Transport, error) {
+	switch network {
+	case "udp":
+		return NewUdpSever(addr, clientCount), nil
+	case "tcp":
+		return NewTcpSever(addr, clientCount), nil
+	case "ws":
+		return NewWebsocketServer(addr, clientCount), nil
+	default:
+		return nil, fmt.Errorf("unknown network %q", network)
+	}
+}
@@ -1,46 +1,49 @@
 package server
 
 import (
-	"bytes"
-	"context"
-	"encoding/binary"
-	"fmt"
 	"log"
 	"net"
-	"time"
-	"unicode/utf8"
+	"sync/atomic"
 
+	"github.com/apfelfrisch/gosnake/game/network/framing"
 	"github.com/golang/snappy"
 )
 
-const HANDSHAKE_REQ = '?'
-const HANDSHAKE_RESP = '!'
-
 func NewUdpSever(addr string, connCount int) *UdpServer {
-	udpAddr, err := net.ResolveUDPAddr("udp", addr)
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	return &UdpServer{
-		addr:        udpAddr,
-		clientCount: connCount,
-		inputChans:  make(map[string]chan rune),
-		outputChans: make(map[string]byteBufferChan),
+		hub:        hub{name: "UDP-SERVER", clientCount: connCount},
+		listenAddr: addr,
 	}
 }
 
 type UdpServer struct {
-	addr        *net.UDPAddr
-	conn        *net.UDPConn
-	clients     []*net.UDPAddr
-	clientCount int
-	inputChans  map[string]chan rune
-	outputChans map[string]byteBufferChan
-	stopChan    chan struct{}
-	isOpen      bool
+	hub
+	listenAddr string
+	addr       net.Addr
+	conn       *net.UDPConn
+	stopChan   chan struct{}
+	isOpen     bool
+	// messageID numbers the messages to all clients, so a client can
+	// tell stale datagrams apart.
+	messageID atomic.Uint32
+}
+
+// udpPeer is a client, told apart by its address.
+type udpPeer struct {
+	server *UdpServer
+	addr   *net.UDPAddr
+}
+
+func (p udpPeer) Addr() net.Addr {
+	return p.addr
+}
+
+func (p udpPeer) write(message []byte) {
+	p.server.write(p.addr, message)
 }
 
+func (p udpPeer) close() {}
+
 func (s *UdpServer) Disconnect() {
 	if s.isOpen {
 		close(s.stopChan)
@@ -49,156 +52,60 @@ func (s *UdpServer) Disconnect() {
 	if s.conn != nil {
 		log.Println("Connection closed")
 		s.conn.Close()
-		s.clients = []*net.UDPAddr{}
-		s.inputChans = make(map[string]chan rune)
-		s.outputChans = make(map[string]byteBufferChan)
+		s.closeAll()
 	}
 }
 
+func (s *UdpServer) Network() string {
+	return "udp"
+}
+
 func (s *UdpServer) IsListining() bool {
 	return s.isOpen
 }
 
-func (s *UdpServer) IsReady() bool {
-	return len(s.clients) == s.clientCount
+// Addr is the address the server is bound to, nil before Listen.
+func (s *UdpServer) Addr() net.Addr {
+	return s.addr
 }
 
-func (s *UdpServer) Listen(ctx context.Context) {
-	var err error
-	s.conn, err = net.ListenUDP("udp", s.addr)
+// Listen opens the socket and handles the clients in the background until
+// Disconnect is called. Port 0 binds a free port.
+func (s *UdpServer) Listen() error {
+	udpAddr, err := net.ResolveUDPAddr("udp", s.listenAddr)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
+	s.conn, err = net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return err
+	}
+
+	s.addr = s.conn.LocalAddr()
 	s.stopChan = make(chan struct{})
 	s.isOpen = true
 
-	buffer := make([]byte, 64)
-
-	for len(s.clients) < s.clientCount {
-		select {
-		case <-ctx.Done():
-			s.Disconnect()
-			return
-		default:
-			if err := s.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
-				log.Fatal("UDP-SERVER:" + err.Error())
-			}
-			n, clientAddr, err := s.conn.ReadFromUDP(buffer)
-
-			if err != nil {
-				if netErr, ok := err.(*net.OpError); ok && !netErr.Timeout() {
-					log.Printf("UDP-SERVER:" + err.Error())
-				}
-				continue
-			}
-
-			if string(buffer[:n]) != string(HANDSHAKE_REQ) {
-				log.Printf("UDP-SERVER:" + fmt.Sprintf("Invalid Handshake: [%s]", string(buffer[:n])))
-				continue
-			}
-
-			if _, ok := s.inputChans[clientAddr.String()]; ok {
-				continue
-			}
-
-			s.clients = append(s.clients, clientAddr)
-			s.inputChans[clientAddr.String()] = make(chan rune, 3)
-			s.outputChans[clientAddr.String()] = make(byteBufferChan, 1)
-
-			go s.handleServerWriting(clientAddr, s.outputChans[clientAddr.String()])
-		}
-	}
-
-	// Start Server Reading after s.addClient
-	// otherwise we get Deadlock
 	go s.handleSeverReading()
-}
-
-func (s *UdpServer) ReadConn(addr *net.UDPAddr) *rune {
-	select {
-	case value := <-s.inputChans[addr.String()]:
-		return &value
-	default:
-		return nil
-	}
-}
-
-func (s *UdpServer) WriteConn(addr *net.UDPAddr, content []byte) {
 
-	select {
-	// Try to write to the channel
-	case s.outputChans[addr.String()] <- byteBuffer{content}:
-	// Otherwise clear channel
-	default:
-		<-s.outputChans[addr.String()]
-		s.outputChans[addr.String()] <- byteBuffer{content}
-	}
-}
-
-func (s *UdpServer) addClient() (*net.UDPAddr, error) {
-	buffer := make([]byte, 64)
-	for {
-		n, clientAddr, err := s.conn.ReadFromUDP(buffer)
-
-		if err != nil {
-			return clientAddr, err
-		}
-
-		if string(buffer[:n]) != string(HANDSHAKE_REQ) {
-			return clientAddr, fmt.Errorf("Invalid Handshake: [%s]", string(buffer[:n]))
-		}
-
-		if _, ok := s.inputChans[clientAddr.String()]; ok {
-			continue
-		}
-
-		s.clients = append(s.clients, clientAddr)
-		s.inputChans[clientAddr.String()] = make(chan rune, 3)
-		s.outputChans[clientAddr.String()] = make(byteBufferChan, 1)
-
-		return clientAddr, nil
-	}
+	return nil
 }
 
 func (s *UdpServer) handleSeverReading() {
-	if err := s.conn.SetReadDeadline(time.Time{}); err != nil {
-		log.Fatal("UDP-SERVER:" + err.Error())
-	}
-
-	buffer := make([]byte, 16)
+	buffer := make([]byte, 512)
 	readConnection := func() {
 		n, remoteAddr, err := s.conn.ReadFromUDP(buffer)
 		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		data := buffer[:n]
-		for len(data) == 0 {
-			return
-		}
-
-		inputChan, ok := s.inputChans[remoteAddr.String()]
-		if !ok {
+			if s.isOpen {
+				log.Println("UDP-SERVER:", err)
+			}
 			return
 		}
-
-		rune, size := utf8.DecodeRune(data)
-
-		if rune == utf8.RuneError && size == 1 {
+		if n == 0 {
 			return
 		}
 
-		if rune == HANDSHAKE_REQ {
-			s.WriteConn(remoteAddr, []byte(string(HANDSHAKE_RESP)))
-		}
-
-		select {
-		case inputChan <- rune:
-		default:
-			<-inputChan
-			inputChan <- rune
-		}
+		s.receive(udpPeer{server: s, addr: remoteAddr}, buffer[:n])
 	}
 
 	for {
@@ -211,23 +118,14 @@ func (s *UdpServer) handleSeverReading() {
 	}
 }
 
-func (s *UdpServer) handleServerWriting(clientAddr *net.UDPAddr, outputChan byteBufferChan) {
-	writemessage := func(message []byte) {
-		compressed := snappy.Encode(nil, message)
-
-		var lengthBuffer bytes.Buffer
-		binary.Write(&lengthBuffer, binary.BigEndian, uint32(len(compressed)))
-
-		s.conn.WriteToUDP(lengthBuffer.Bytes(), clientAddr)
-		s.conn.WriteToUDP(compressed, clientAddr)
+func (s *UdpServer) write(addr *net.UDPAddr, message []byte) {
+	datagrams, err := framing.Encode(s.messageID.Add(1), snappy.Encode(nil, message))
+	if err != nil {
+		log.Println("UDP-SERVER: Could not send message:", err)
+		return
 	}
 
-	for {
-		select {
-		case <-s.stopChan:
-			return
-		case message := <-outputChan:
-			writemessage(message[0])
-		}
+	for _, datagram := range datagrams {
+		s.conn.WriteToUDP(datagram, addr)
 	}
 }
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/coder/websocket"
+	"github.com/golang/snappy"
+)
+
+// NewWebsocketServer serves the clients over WebSocket, so browser clients
+// can join.
+func NewWebsocketServer(addr string, connCount int) *WebsocketServer {
+	return &WebsocketServer{
+		hub:        hub{name: "WS-SERVER", clientCount: connCount},
+		listenAddr: addr,
+	}
+}
+
+type WebsocketServer struct {
+	hub
+	listenAddr     string
+	originPatterns []string
+	listener       net.Listener
+	server         *http.Server
+	isOpen         bool
+}
+
+// websocketPeer is a client connection. A client that reconnects gets a new
+// one.
+type websocketPeer struct {
+	addr net.Addr
+	conn *websocket.Conn
+}
+
+func (p *websocketPeer) Addr() net.Addr {
+	return p.addr
+}
+
+func (p *websocketPeer) write(message []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), ClientTimeout)
+	defer cancel()
+
+	if err := p.conn.Write(ctx, websocket.MessageBinary, snappy.Encode(nil, message)); err != nil {
+		p.close()
+	}
+}
+
+func (p *websocketPeer) close() {
+	p.conn.CloseNow()
+}
+
+// AllowOrigins lets browser pages from other hosts join, as host patterns
+// like "example.com" or "*.example.com". "*" allows every page. Without
+// patterns only pages of the server host and clients without a browser can
+// join. It must be called before Listen.
+func (s *WebsocketServer) AllowOrigins(patterns ...string) {
+	s.originPatterns = patterns
+}
+
+func (s *WebsocketServer) Disconnect() {
+	if s.isOpen {
+		s.isOpen = false
+		log.Println("Connection closed")
+		s.server.Close()
+		s.closeAll()
+	}
+}
+
+func (s *WebsocketServer) Network() string {
+	return "ws"
+}
+
+func (s *WebsocketServer) IsListining() bool {
+	return s.isOpen
+}
+
+// Addr is the address the server is bound to, nil before Listen.
+func (s *WebsocketServer) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// Listen opens the socket and handles the clients in the background until
+// Disconnect is called. Port 0 binds a free port.
+func (s *WebsocketServer) Listen() error {
+	var err error
+	s.listener, err = net.Listen("tcp", s.listenAddr)
+	if err != nil {
+		return err
+	}
+
+	s.server = &http.Server{Handler: s}
+	s.isOpen = true
+
+	go func() {
+		if err := s.server.Serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
+			log.Println("WS-SERVER:", err)
+		}
+	}()
+
+	return nil
+}
+
+// ServeHTTP upgrades a request on any path and passes the messages of the
+// connection on until it closes or stays silent for ClientTimeout.
+func (s *WebsocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		OriginPatterns: s.originPatterns,
+	})
+	if err != nil {
+		log.Println("WS-SERVER:", err)
+		return
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	if err != nil {
+		conn.Close(websocket.StatusInternalError, "unknown remote address")
+		return
+	}
+
+	p := &websocketPeer{addr: addr, conn: conn}
+	defer p.close()
+
+	for {
+		ctx, cancel := context.WithTimeout(r.Context(), ClientTimeout)
+		_, message, err := conn.Read(ctx)
+		cancel()
+		if err != nil {
+			return
+		}
+
+		s.receive(p, message)
+	}
+}
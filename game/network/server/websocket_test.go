@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apfelfrisch/gosnake/game/network/client"
+	"github.com/apfelfrisch/gosnake/game/network/payload"
+	"github.com/coder/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWebsocketRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws := NewWebsocketServer("", 1)
+	httpServer := httptest.NewServer(ws)
+	defer httpServer.Close()
+	defer ws.closeAll()
+
+	addr := "ws://" + strings.TrimPrefix(httpServer.URL, "http://")
+	wsClient := client.NewWebsocketClient(addr, payload.NewHello(payload.Profile{Name: "Browser"}, ""))
+	if err := wsClient.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer wsClient.Disconnect()
+
+	if profiles := ws.Profiles(); len(profiles) != 1 || profiles[0].Name != "Browser" {
+		t.Fatalf("got profiles %v after the handshake", profiles)
+	}
+
+	wsClient.Write('w', 0)
+	var key *rune
+	for key == nil && ctx.Err() == nil {
+		key = ws.ReadSlot(0, 0)
+		time.Sleep(time.Millisecond)
+	}
+	if key == nil || *key != 'w' {
+		t.Fatal("the server did not get the input")
+	}
+
+	ws.WriteSlot(0, marshal(payload.Payload{Sequence: 1, Tick: 7}.ToProto()))
+	state := &payload.ProtoPayload{}
+	for ctx.Err() == nil {
+		if data := wsClient.Read(); len(data) > 0 && proto.Unmarshal(data, state) == nil && state.Sequence == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if state.Sequence != 1 || state.Tick != 7 {
+		t.Fatal("the client did not get the state")
+	}
+}
+
+func TestWebsocketOrigins(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws := NewWebsocketServer("", 1)
+	httpServer := httptest.NewServer(ws)
+	defer httpServer.Close()
+	defer ws.closeAll()
+
+	addr := "ws://" + strings.TrimPrefix(httpServer.URL, "http://")
+	dial := func() error {
+		conn, _, err := websocket.Dial(ctx, addr, &websocket.DialOptions{
+			HTTPHeader: http.Header{"Origin": {"https://snake.example"}},
+		})
+		if err == nil {
+			conn.CloseNow()
+		}
+		return err
+	}
+
+	if dial() == nil {
+		t.Fatal("a page of another host joined without being allowed")
+	}
+
+	ws.AllowOrigins("*.example")
+	if err := dial(); err != nil {
+		t.Fatal("an allowed page could not join:", err)
+	}
+}
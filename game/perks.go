@@ -18,13 +18,7 @@ func (ps *Perks) Get(pt PerkType) Perk {
 		*ps = make(Perks)
 	}
 
-	if p, ok := (*ps)[pt]; ok {
-		return p
-	}
-
-	ps.add(pt, 0)
-
-	return Perk{0}
+	return (*ps)[pt]
 }
 
 func (ps *Perks) set(pt PerkType, p Perk) {
@@ -57,6 +51,30 @@ func (ps *Perks) reload(pt PerkType, usages uint16) {
 	ps.set(pt, p)
 }
 
+// activate starts a timed perk or extends its remaining ticks.
+func (ps *Perks) activate(pt PerkType, ticks uint16) {
+	p := ps.Get(pt)
+
+	p.Ticks += ticks
+
+	ps.set(pt, p)
+}
+
+// Active reports if a timed perk has ticks left.
+func (ps *Perks) Active(pt PerkType) bool {
+	return ps.Get(pt).Ticks > 0
+}
+
+// tick counts down all active timed perks.
+func (ps Perks) tick() {
+	for pt, p := range ps {
+		if p.Ticks > 0 {
+			p.Ticks -= 1
+			ps[pt] = p
+		}
+	}
+}
+
 type PerkType int
 
 func (pt PerkType) String() string {
@@ -65,6 +83,14 @@ func (pt PerkType) String() string {
 		return "Walk Wall"
 	case PerkTypeDash:
 		return "Stash"
+	case PerkTypeShield:
+		return "Shield"
+	case PerkTypeGhost:
+		return "Ghost"
+	case PerkTypeMagnet:
+		return "Magnet"
+	case PerkTypeReverse:
+		return "Reverse"
 	}
 
 	return "Unkown"
@@ -73,10 +99,26 @@ func (pt PerkType) String() string {
 const (
 	PerkTypeWalkWall PerkType = 1
 	PerkTypeDash     PerkType = 2
+	// PerkTypeShield saves the snake from one collision.
+	PerkTypeShield PerkType = 3
+	// PerkTypeGhost lets the snake pass through other snakes while active.
+	PerkTypeGhost PerkType = 4
+	// PerkTypeMagnet pulls nearby candies to the head while active.
+	PerkTypeMagnet PerkType = 5
+	// PerkTypeReverse swaps head and tail of the snake.
+	PerkTypeReverse PerkType = 6
 )
 
+// IsTimed reports if the perk is active for a number of ticks after it was
+// collected, instead of having usages.
+func (pt PerkType) IsTimed() bool {
+	return pt == PerkTypeGhost || pt == PerkTypeMagnet
+}
+
 type Perk struct {
 	Usages uint16 `json:"u"`
+	// Ticks is the number of ticks a timed perk stays active.
+	Ticks uint16 `json:"t"`
 }
 
 func (p *Perk) reload(usages uint16) {
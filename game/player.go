@@ -1,5 +1,10 @@
 package game
 
+import (
+	"maps"
+	"slices"
+)
+
 type Snake struct {
 	Perks        Perks      `json:"pk"`
 	Lives        uint8      `json:"li"`
@@ -7,17 +12,24 @@ type Snake struct {
 	Direction    Direction  `json:"dr"`
 	NewDirection Direction  `json:"nd"`
 	Points       uint16     `json:"pt"`
-	grows        uint8
+	// Alive is false for snakes that crashed in a battle royale round or
+	// lost their last life in the match.
+	Alive bool `json:"al"`
+	// Team is the team of the snake, 0 if it plays for itself.
+	Team  uint8 `json:"tm"`
+	grows uint8
 }
 
-func NewSnake(x uint16, y uint16, direction Direction) Snake {
+func NewSnake(x uint16, y uint16, direction Direction, team uint8, rules Rules) Snake {
 	return Snake{
-		Lives:        10,
+		Team:         team,
+		Lives:        rules.Lives,
 		Points:       0,
-		Perks:        Perks{PerkTypeWalkWall: {Usages: 1}, PerkTypeDash: {Usages: 1}},
+		Perks:        Perks{PerkTypeWalkWall: {Usages: rules.StartPerks}, PerkTypeDash: {Usages: rules.StartPerks}},
 		Direction:    direction,
 		NewDirection: direction,
 		Occupied:     []Position{{X: x, Y: y}},
+		Alive:        true,
 		grows:        0,
 	}
 }
@@ -30,7 +42,14 @@ func (snake *Snake) reset(x uint16, y uint16, direction Direction) {
 	snake.Occupied = []Position{{X: x, Y: y}}
 	snake.Direction = direction
 	snake.NewDirection = direction
+	snake.Alive = true
 	snake.grows = 0
+
+	for pt, perk := range snake.Perks {
+		// Timed perks end with the round
+		perk.Ticks = 0
+		snake.Perks[pt] = perk
+	}
 }
 
 func (snake *Snake) ChangeDirection(direction Direction) {
@@ -75,6 +94,15 @@ func (snake *Snake) body() []Position {
 	return snake.Occupied[:len(snake.Occupied)-1]
 }
 
+// snapshot returns a copy of the snake that does not share its fields.
+func (snake *Snake) snapshot() Snake {
+	clone := *snake
+	clone.Occupied = slices.Clone(snake.Occupied)
+	clone.Perks = maps.Clone(snake.Perks)
+
+	return clone
+}
+
 func (snake *Snake) move() {
 	if len(snake.Occupied) == 0 {
 		return
@@ -93,27 +121,92 @@ func (snake *Snake) move() {
 	snake.Direction = snake.NewDirection
 }
 
-func (snake *Snake) walkWalls(game *Game) {
+// reverse swaps head and tail, the snake moves on in the direction its
+// tail came from.
+func (snake *Snake) reverse() {
+	slices.Reverse(snake.Occupied)
+
+	direction := snake.Direction.Opposite()
+	if len(snake.Occupied) > 1 {
+		neck := snake.Occupied[len(snake.Occupied)-2]
+		for _, dir := range []Direction{North, East, South, West} {
+			if neck.Move(dir) == snake.Head() {
+				direction = dir
+			}
+		}
+	}
+
+	snake.Direction = direction
+	snake.NewDirection = direction
+}
+
+// walkWalls moves a head on the border to the opposite side and reports if
+// the perk was used for it.
+func (snake *Snake) walkWalls(width, height uint16) bool {
 	position := snake.Head()
 
 	if ok := snake.Perks.use(PerkTypeWalkWall); !ok {
-		return
+		return false
 	}
 
 	// Walk through Walls
-	if position.X > game.Width()-1 {
+	if position.X > width-1 {
 		position.X = 2
 	} else if position.X == 1 {
-		position.X = game.Width() - 1
-	} else if position.Y > game.Height()-1 {
+		position.X = width - 1
+	} else if position.Y > height-1 {
 		position.Y = 2
 	} else if position.Y == 1 {
-		position.Y = game.Height() - 1
+		position.Y = height - 1
 	} else {
 		// Perk was not needed
 		snake.Perks.reload(PerkTypeWalkWall, 1)
-		return
+		return false
 	}
 
 	snake.Occupied = append(snake.Occupied[:len(snake.Occupied)-1], position)
+
+	return true
+}
+
+// Predict returns the snake one tick later, after the input was applied the
+// way Step applies it. Collisions and perk candies are left to the server,
+// so a client can show its own snake before the server confirms the move.
+func (snake *Snake) Predict(input Input, width, height uint16, candies []Candy, rules Rules) Snake {
+	next := snake.snapshot()
+
+	step := func() {
+		next.move()
+		next.walkWalls(width, height)
+		for _, candy := range candies {
+			if candy.CandyTpe == CandyGrow && candy.Position == next.Head() {
+				next.eat(rules.GrowSize)
+			}
+		}
+	}
+
+	switch input {
+	case InputNorth:
+		next.ChangeDirection(North)
+	case InputSouth:
+		next.ChangeDirection(South)
+	case InputWest:
+		next.ChangeDirection(West)
+	case InputEast:
+		next.ChangeDirection(East)
+	case InputDash:
+		if next.Perks.use(PerkTypeDash) {
+			for i := uint8(0); i < rules.DashLength; i++ {
+				step()
+			}
+		}
+	case InputReverse:
+		if next.Perks.use(PerkTypeReverse) {
+			next.reverse()
+		}
+	}
+
+	step()
+
+	return next
 }
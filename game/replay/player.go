@@ -0,0 +1,78 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+// Player rebuilds a recorded match frame by frame.
+type Player struct {
+	replay *Replay
+	game   *game.Game
+	frame  int
+	events []game.Event
+}
+
+func NewPlayer(replay *Replay) (*Player, error) {
+	g, err := replay.NewGame()
+	if err != nil {
+		return nil, err
+	}
+	if g.Level() != replay.Level {
+		return nil, fmt.Errorf("replay starts at level %d, only level %d is supported", replay.Level, g.Level())
+	}
+
+	return &Player{replay: replay, game: g}, nil
+}
+
+func (p *Player) Game() *game.Game {
+	return p.game
+}
+
+func (p *Player) Frame() int {
+	return p.frame
+}
+
+func (p *Player) Len() int {
+	return len(p.replay.Frames)
+}
+
+func (p *Player) Finished() bool {
+	return p.frame >= len(p.replay.Frames)
+}
+
+// Events returns the events of the last step.
+func (p *Player) Events() []game.Event {
+	return p.events
+}
+
+// Step advances the game by one recorded frame.
+func (p *Player) Step() bool {
+	if p.Finished() {
+		return false
+	}
+
+	p.game.Step(p.replay.Frames[p.frame])
+	p.events = p.game.DrainEvents()
+	p.frame++
+
+	return true
+}
+
+// Seek moves to the given frame. Seeking backwards rebuilds the game from the
+// seed, since the simulation can only run forward.
+func (p *Player) Seek(frame int) {
+	frame = max(0, min(frame, p.Len()))
+
+	if frame < p.frame {
+		// NewPlayer built the game from the same header already
+		p.game, _ = p.replay.NewGame()
+		p.frame = 0
+		p.events = nil
+	}
+
+	for p.frame < frame {
+		p.Step()
+	}
+}
@@ -0,0 +1,217 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+const magic = "GSNR"
+
+// Version 2 added custom maps, version 3 the rules to the header.
+const version = 3
+
+var ErrInvalidFile = errors.New("not a gosnake replay")
+
+// Header holds everything needed to rebuild the game a replay was recorded from.
+type Header struct {
+	Seed    uint64
+	Players uint16
+	Width   uint16
+	Height  uint16
+	Level   uint16
+	Map     *game.Map
+	Rules   game.Rules
+}
+
+func (h Header) NewGame() (*game.Game, error) {
+	if h.Map != nil {
+		return game.NewCustomGame(int(h.Players), h.Map, h.Rules, h.Seed), nil
+	}
+
+	return game.NewGame(int(h.Players), int(h.Width), int(h.Height), h.Rules, h.Seed)
+}
+
+// Replay is a recorded match: the header and the inputs of every game step.
+type Replay struct {
+	Header
+	Frames [][]game.Input
+}
+
+// Recorder writes a replay file while a match is running. Every frame is
+// flushed right away, so the file stays usable if the game crashes.
+type Recorder struct {
+	w       *bufio.Writer
+	out     io.Writer
+	players int
+}
+
+func NewRecorder(w io.Writer, g *game.Game) (*Recorder, error) {
+	r := &Recorder{
+		w:       bufio.NewWriter(w),
+		out:     w,
+		players: len(g.Players()),
+	}
+
+	header := Header{
+		Seed:    g.Seed(),
+		Players: uint16(len(g.Players())),
+		Width:   g.Width(),
+		Height:  g.Height(),
+		Level:   g.Level(),
+		Map:     g.CustomMap(),
+		Rules:   g.Rules(),
+	}
+
+	rulesData, err := json.Marshal(header.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapData []byte
+	if header.Map != nil {
+		mapData, _ = header.Map.MarshalText()
+	}
+
+	r.w.WriteString(magic)
+	r.w.WriteByte(version)
+	binary.Write(r.w, binary.LittleEndian, header.Seed)
+	for _, v := range []uint16{header.Players, header.Width, header.Height, header.Level} {
+		r.writeUvarint(uint64(v))
+	}
+	r.writeUvarint(uint64(len(mapData)))
+	r.w.Write(mapData)
+	r.writeUvarint(uint64(len(rulesData)))
+	r.w.Write(rulesData)
+
+	return r, r.w.Flush()
+}
+
+// Record appends the inputs of one game step. Only pressed keys are stored.
+func (r *Recorder) Record(inputs []game.Input) error {
+	if len(inputs) != r.players {
+		return fmt.Errorf("got %d inputs for %d players", len(inputs), r.players)
+	}
+
+	pressed := 0
+	for _, input := range inputs {
+		if input != game.InputNone {
+			pressed++
+		}
+	}
+
+	r.writeUvarint(uint64(pressed))
+	for playerIndex, input := range inputs {
+		if input != game.InputNone {
+			r.writeUvarint(uint64(playerIndex))
+			r.writeUvarint(uint64(input))
+		}
+	}
+
+	return r.w.Flush()
+}
+
+func (r *Recorder) Close() error {
+	err := r.w.Flush()
+
+	if closer, ok := r.out.(io.Closer); ok {
+		if cerr := closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+func (r *Recorder) writeUvarint(v uint64) {
+	r.w.Write(binary.AppendUvarint(nil, v))
+}
+
+func Read(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	head := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(br, head); err != nil || string(head[:len(magic)]) != magic {
+		return nil, ErrInvalidFile
+	}
+	fileVersion := head[len(magic)]
+	if fileVersion < 1 || fileVersion > version {
+		return nil, fmt.Errorf("unsupported replay version %d", fileVersion)
+	}
+
+	replay := &Replay{Header: Header{Rules: game.DefaultRules()}}
+	if err := binary.Read(br, binary.LittleEndian, &replay.Seed); err != nil {
+		return nil, fmt.Errorf("could not read replay header: %w", err)
+	}
+	for _, v := range []*uint16{&replay.Players, &replay.Width, &replay.Height, &replay.Level} {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("could not read replay header: %w", err)
+		}
+		*v = uint16(n)
+	}
+
+	if fileVersion >= 2 {
+		mapLen, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("could not read replay header: %w", err)
+		}
+		if mapLen > 0 {
+			mapData := make([]byte, mapLen)
+			if _, err := io.ReadFull(br, mapData); err != nil {
+				return nil, fmt.Errorf("could not read replay map: %w", err)
+			}
+			if replay.Map, err = game.ParseMap(bytes.NewReader(mapData)); err != nil {
+				return nil, fmt.Errorf("could not read replay map: %w", err)
+			}
+		}
+	}
+
+	if fileVersion >= 3 {
+		rulesLen, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("could not read replay header: %w", err)
+		}
+		rulesData := make([]byte, rulesLen)
+		if _, err := io.ReadFull(br, rulesData); err != nil {
+			return nil, fmt.Errorf("could not read replay rules: %w", err)
+		}
+		if replay.Rules, err = game.ParseRules(bytes.NewReader(rulesData)); err != nil {
+			return nil, fmt.Errorf("could not read replay rules: %w", err)
+		}
+	}
+
+	for {
+		pressed, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return replay, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read frame %d: %w", len(replay.Frames), err)
+		}
+
+		frame := make([]game.Input, replay.Players)
+		for i := uint64(0); i < pressed; i++ {
+			playerIndex, err := binary.ReadUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("could not read frame %d: %w", len(replay.Frames), err)
+			}
+			input, err := binary.ReadUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("could not read frame %d: %w", len(replay.Frames), err)
+			}
+			if playerIndex >= uint64(replay.Players) {
+				return nil, fmt.Errorf("frame %d: invalid player index %d", len(replay.Frames), playerIndex)
+			}
+			frame[playerIndex] = game.Input(input)
+		}
+
+		replay.Frames = append(replay.Frames, frame)
+	}
+}
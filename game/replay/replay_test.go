@@ -0,0 +1,74 @@
+package replay
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/apfelfrisch/gosnake/game"
+)
+
+var testKeys = []game.Input{game.InputNone, game.InputNone, game.InputNorth, game.InputSouth, game.InputWest, game.InputEast, game.InputDash}
+
+func TestReplayReproducesFinalState(t *testing.T) {
+	g, err := game.NewGame(2, 50, 50, game.DefaultRules(), 99)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var file bytes.Buffer
+	recorder, err := NewRecorder(&file, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewPCG(3, 3))
+	for i := 0; i < 1500; i++ {
+		inputs := make([]game.Input, len(g.Players()))
+		for j := range inputs {
+			inputs[j] = testKeys[rng.IntN(len(testKeys))]
+		}
+		if g.State() != game.Ongoing {
+			inputs[0] = game.InputConfirm
+		}
+
+		g.Step(inputs)
+		if err := recorder.Record(inputs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replay, err := Read(&file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replay.Frames) != 1500 {
+		t.Fatalf("got %d frames, expected 1500", len(replay.Frames))
+	}
+
+	player, err := NewPlayer(replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for player.Step() {
+	}
+
+	replayed := player.Game()
+	if replayed.Level() != g.Level() || replayed.State() != g.State() || replayed.Ticks() != g.Ticks() {
+		t.Fatalf("level, state or ticks differ: %d/%d/%d and %d/%d/%d", replayed.Level(), replayed.State(), replayed.Ticks(), g.Level(), g.State(), g.Ticks())
+	}
+	if !reflect.DeepEqual(replayed.Players(), g.Players()) {
+		t.Fatal("players differ")
+	}
+	if !reflect.DeepEqual(replayed.Candies(), g.Candies()) {
+		t.Fatal("candies differ")
+	}
+
+	// Seeking back rebuilds the game from the seed
+	player.Seek(0)
+	player.Seek(player.Len())
+	if !reflect.DeepEqual(player.Game().Players(), g.Players()) {
+		t.Fatal("players differ after seeking")
+	}
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	rulesAsset "github.com/apfelfrisch/gosnake/game/assets/rules"
+)
+
+// HeadOnRule decides who survives when two snake heads meet.
+type HeadOnRule string
+
+const (
+	HeadOnBothDie    HeadOnRule = "both_die"
+	HeadOnLongerWins HeadOnRule = "longer_wins"
+)
+
+// GameMode decides how a round is won.
+type GameMode string
+
+const (
+	// ModeClassic finishes the round on the first crash, a level is won by
+	// eating candies.
+	ModeClassic GameMode = "classic"
+	// ModeBattleRoyale removes crashed snakes from the round, the round ends
+	// when one snake is left.
+	ModeBattleRoyale GameMode = "battle_royale"
+)
+
+// Rules are the settings of a match. They are shared by the game and the
+// server and sent to every client.
+type Rules struct {
+	Name string `json:"name"`
+	// GrowSize is the number of fields a snake grows per eaten candy.
+	GrowSize uint8 `json:"grow_size"`
+	// MapSwitch is the number of candies all players together need to eat
+	// to finish a level.
+	MapSwitch uint16 `json:"map_switch"`
+	Lives     uint8  `json:"lives"`
+	// StartPerks is the number of usages every perk has at the start.
+	StartPerks uint16 `json:"start_perks"`
+	// PerkSpawnOdds is the 1 in N chance per tick that a perk candy spawns,
+	// 0 disables perk candies.
+	PerkSpawnOdds uint32 `json:"perk_spawn_odds"`
+	DashLength    uint8  `json:"dash_length"`
+	// PerkTicks is the number of ticks the ghost and magnet perks are active.
+	PerkTicks  uint16 `json:"perk_ticks"`
+	TickMillis uint32 `json:"tick_ms"`
+	// HeadOn decides head-on collisions, snakes of equal length always both die.
+	HeadOn HeadOnRule `json:"head_on"`
+	Mode   GameMode   `json:"mode"`
+	// ShrinkTicks is the number of ticks after which a battle royale map
+	// shrinks by one ring of walls, 0 disables shrinking.
+	ShrinkTicks uint32 `json:"shrink_ticks"`
+	// Teams is the number of teams the player slots are split into, 0 means
+	// every player plays for themselves. Teams share their lives and points.
+	Teams uint8 `json:"teams"`
+	// FriendlyFire makes snakes of the same team lethal to each other.
+	FriendlyFire bool `json:"friendly_fire"`
+}
+
+const DefaultRulesPreset = "classic"
+
+// DefaultRules returns the classic rules.
+func DefaultRules() Rules {
+	rules, err := RulesPreset(DefaultRulesPreset)
+	if err != nil {
+		panic(err)
+	}
+
+	return rules
+}
+
+// RulesPresets returns the names of the embedded rule presets.
+func RulesPresets() []string {
+	files, _ := fs.Glob(rulesAsset.Files, "*.json")
+
+	presets := make([]string, len(files))
+	for i, file := range files {
+		presets[i] = strings.TrimSuffix(file, ".json")
+	}
+
+	return presets
+}
+
+func RulesPreset(name string) (Rules, error) {
+	f, err := rulesAsset.Files.Open(name + ".json")
+	if err != nil {
+		return Rules{}, fmt.Errorf("unknown rules preset %q", name)
+	}
+	defer f.Close()
+
+	return ParseRules(f)
+}
+
+// LoadRules loads a rules preset by name or a rules file by path.
+func LoadRules(nameOrFile string) (Rules, error) {
+	if rules, err := RulesPreset(nameOrFile); err == nil {
+		return rules, nil
+	}
+
+	f, err := os.Open(nameOrFile)
+	if err != nil {
+		return Rules{}, err
+	}
+	defer f.Close()
+
+	rules, err := ParseRules(f)
+	if err != nil {
+		return Rules{}, fmt.Errorf("%s: %w", nameOrFile, err)
+	}
+
+	if rules.Name == "" {
+		rules.Name = strings.TrimSuffix(filepath.Base(nameOrFile), filepath.Ext(nameOrFile))
+	}
+
+	return rules, nil
+}
+
+// ParseRules reads JSON encoded rules. Missing settings are taken from the
+// classic rules, unknown settings are an error.
+func ParseRules(r io.Reader) (Rules, error) {
+	var rules Rules
+	if f, err := rulesAsset.Files.Open(DefaultRulesPreset + ".json"); err == nil {
+		json.NewDecoder(f).Decode(&rules)
+		f.Close()
+	}
+	rules.Name = ""
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return Rules{}, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	return rules, rules.Validate()
+}
+
+func (rules Rules) Validate() error {
+	var errs []error
+
+	if rules.GrowSize == 0 {
+		errs = append(errs, errors.New("grow_size must be greater than 0"))
+	}
+	if rules.MapSwitch == 0 {
+		errs = append(errs, errors.New("map_switch must be greater than 0"))
+	}
+	if rules.Lives == 0 {
+		errs = append(errs, errors.New("lives must be greater than 0"))
+	}
+	if rules.PerkTicks == 0 {
+		errs = append(errs, errors.New("perk_ticks must be greater than 0"))
+	}
+	if rules.TickMillis == 0 {
+		errs = append(errs, errors.New("tick_ms must be greater than 0"))
+	}
+	if rules.HeadOn != HeadOnBothDie && rules.HeadOn != HeadOnLongerWins {
+		errs = append(errs, fmt.Errorf("head_on must be %q or %q", HeadOnBothDie, HeadOnLongerWins))
+	}
+
+	if rules.Teams == 1 {
+		errs = append(errs, errors.New("teams must be 0 or at least 2"))
+	}
+	if rules.Mode != ModeClassic && rules.Mode != ModeBattleRoyale {
+		errs = append(errs, fmt.Errorf("mode must be %q or %q", ModeClassic, ModeBattleRoyale))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (rules Rules) TickDuration() time.Duration {
+	return time.Duration(rules.TickMillis) * time.Millisecond
+}
+
+func (rules Rules) PerksEnabled() bool {
+	return rules.PerkSpawnOdds > 0
+}
+
+// TeamOf returns the team of a player slot, starting at 1. Slots are
+// assigned to the teams in turns, without teams it returns 0.
+func (rules Rules) TeamOf(slot int) uint8 {
+	if rules.Teams == 0 {
+		return 0
+	}
+
+	return uint8(slot%int(rules.Teams)) + 1
+}
+
+func (rules Rules) IsBattleRoyale() bool {
+	return rules.Mode == ModeBattleRoyale
+}
@@ -11,6 +11,19 @@ const (
 	West
 )
 
+func (d Direction) Opposite() Direction {
+	switch d {
+	case North:
+		return South
+	case South:
+		return North
+	case East:
+		return West
+	default:
+		return East
+	}
+}
+
 type Field rune
 
 const (
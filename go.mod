@@ -3,10 +3,12 @@ module github.com/apfelfrisch/gosnake
 go 1.23.2
 
 require (
+	github.com/coder/websocket v1.8.12
 	github.com/golang/snappy v0.0.4
 	github.com/hajimehoshi/ebiten/v2 v2.8.6
 	github.com/joelschutz/stagehand v1.1.1
 	golang.org/x/image v0.20.0
+	golang.org/x/sys v0.25.0
 	google.golang.org/protobuf v1.36.1
 )
 
@@ -19,6 +21,5 @@ require (
 	github.com/hajimehoshi/go-mp3 v0.3.4 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/sync v0.8.0 // indirect
-	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
 )